@@ -0,0 +1,99 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ApplyJob is ApplyJobContext with a background context.
+func (client Client) ApplyJob(jobName, jobConfigXML string) (ApplyResult, error) {
+	return client.ApplyJobContext(context.Background(), jobName, jobConfigXML)
+}
+
+// ApplyJobContext makes the named job have the given XML job config: it creates the job if it does not exist,
+// and otherwise replaces its config unless the live config already matches.  Configs are compared after
+// normalization, so differences in the XML declaration, comments, indentation, attribute order or empty-element
+// syntax do not count as changes.  The result says which of the three happened.
+func (client Client) ApplyJobContext(ctx context.Context, jobName, jobConfigXML string) (ApplyResult, error) {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return "", err
+	}
+	desired, err := normalizeConfigXML([]byte(jobConfigXML))
+	if err != nil {
+		return "", fmt.Errorf("jenkins: config for job %s is not well-formed XML: %w", jobName, err)
+	}
+
+	exists, err := client.JobExistsContext(ctx, jobName)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		err := client.CreateJobContext(ctx, jobName, jobConfigXML)
+		if err == nil {
+			return ApplyCreated, nil
+		}
+		if !IsConflict(err) {
+			return "", err
+		}
+		// Someone else created the job since we looked.  Compare against their config instead.
+	}
+
+	data, err := client.getJobConfigXML(ctx, jobName)
+	if err != nil {
+		return "", err
+	}
+	// A live config that cannot be normalized cannot match, so it is simply replaced.
+	if live, err := normalizeConfigXML(data); err == nil && live == desired {
+		return ApplyUnchanged, nil
+	}
+	if err := client.UpdateJobConfigContext(ctx, jobName, jobConfigXML); err != nil {
+		return "", err
+	}
+	return ApplyUpdated, nil
+}
+
+// normalizeConfigXML re-encodes an XML document without its declaration, processing instructions, comments and
+// whitespace-only text, and with the attributes of each element sorted by name.  Two configs Jenkins would read
+// the same way normalize to the same string.
+func normalizeConfigXML(document []byte) (string, error) {
+	// Jenkins writes XML 1.1 declarations, which encoding/xml refuses, so the declaration is dropped up front.
+	decoder := xml.NewDecoder(bytes.NewReader(stripXMLDeclaration(document)))
+	var normalized bytes.Buffer
+	encoder := xml.NewEncoder(&normalized)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.ProcInst, xml.Comment, xml.Directive:
+			continue
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		case xml.StartElement:
+			sort.Slice(t.Attr, func(i, j int) bool {
+				if t.Attr[i].Name.Space != t.Attr[j].Name.Space {
+					return t.Attr[i].Name.Space < t.Attr[j].Name.Space
+				}
+				return t.Attr[i].Name.Local < t.Attr[j].Name.Local
+			})
+		}
+		if err := encoder.EncodeToken(token); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return "", err
+	}
+	return normalized.String(), nil
+}
@@ -0,0 +1,123 @@
+package jenkins
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const appliedConfig = `<?xml version='1.0' encoding='UTF-8'?>
+<project>
+  <description>built by the pipeline</description>
+  <scm class="hudson.scm.NullSCM" plugin="scm-api"/>
+  <disabled>false</disabled>
+</project>`
+
+func TestApplyJobCreates(t *testing.T) {
+	created := false
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/job/service/api/json":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST" && r.URL.Path == "/createItem":
+			if r.URL.Query().Get("name") != "service" {
+				t.Fatalf("want name=service but got %s\n", r.URL.RawQuery)
+			}
+			created = true
+		default:
+			t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	result, err := jenkinsClient.ApplyJob("service", appliedConfig)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if result != ApplyCreated || !created {
+		t.Fatalf("want %s and a create request but got %s, created=%v\n", ApplyCreated, result, created)
+	}
+}
+
+func TestApplyJobUnchanged(t *testing.T) {
+	// The same config as Jenkins might serve it: XML 1.1, other indentation, other attribute order,
+	// expanded empty elements and a comment.
+	live := `<?xml version='1.1' encoding='UTF-8'?>
+<project>
+    <!-- managed -->
+    <description>built by the pipeline</description>
+    <scm plugin="scm-api" class="hudson.scm.NullSCM"></scm>
+    <disabled>false</disabled>
+</project>
+`
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/job/service/api/json":
+			fmt.Fprintln(w, `{"name":"service"}`)
+		case r.Method == "GET" && r.URL.Path == "/job/service/config.xml":
+			fmt.Fprint(w, live)
+		default:
+			t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	result, err := jenkinsClient.ApplyJob("service", appliedConfig)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if result != ApplyUnchanged {
+		t.Fatalf("want %s but got %s\n", ApplyUnchanged, result)
+	}
+}
+
+func TestApplyJobUpdates(t *testing.T) {
+	var posted string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/job/service/api/json":
+			fmt.Fprintln(w, `{"name":"service"}`)
+		case r.Method == "GET" && r.URL.Path == "/job/service/config.xml":
+			fmt.Fprint(w, `<project><description>hand edited</description></project>`)
+		case r.Method == "POST" && r.URL.Path == "/job/service/config.xml":
+			body, _ := io.ReadAll(r.Body)
+			posted = string(body)
+		default:
+			t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	result, err := jenkinsClient.ApplyJob("service", appliedConfig)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if result != ApplyUpdated {
+		t.Fatalf("want %s but got %s\n", ApplyUpdated, result)
+	}
+	if posted != appliedConfig {
+		t.Fatalf("want the desired config posted but got %s\n", posted)
+	}
+}
+
+func TestApplyJobMalformedConfig(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if _, err := jenkinsClient.ApplyJob("service", "<project>"); err == nil {
+		t.Fatalf("want an error for a malformed config\n")
+	}
+}
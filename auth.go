@@ -0,0 +1,84 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	// Authenticator adds credentials to a request before it is sent to Jenkins.
+	Authenticator interface {
+		Authenticate(req *http.Request) error
+	}
+
+	// TokenProvider returns the bearer token to present to Jenkins.  It is called for every request, so a provider
+	// backed by an identity provider can rotate tokens; caching them is up to the provider.
+	TokenProvider func(ctx context.Context) (string, error)
+
+	basicAuth struct {
+		username string
+		password string
+	}
+
+	bearerAuth struct {
+		provider TokenProvider
+	}
+
+	noAuth struct{}
+)
+
+// BasicAuth authenticates with a username and password using HTTP basic authentication.
+func BasicAuth(username, password string) Authenticator {
+	return basicAuth{username: username, password: password}
+}
+
+// APIToken authenticates as username with one of that user's Jenkins API tokens.  Jenkins expects API tokens in
+// place of the password in HTTP basic authentication.
+func APIToken(username, token string) Authenticator {
+	return basicAuth{username: username, password: token}
+}
+
+// BearerToken authenticates with a bearer token, such as an OIDC access token, obtained from provider.
+func BearerToken(provider TokenProvider) Authenticator {
+	return bearerAuth{provider: provider}
+}
+
+// NoAuth sends requests anonymously, for Jenkins instances that allow anonymous read access.
+func NoAuth() Authenticator {
+	return noAuth{}
+}
+
+// WithAuthenticator makes the client authenticate with authenticator instead of the username and password
+// given to NewClient.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(o *options) {
+		o.authenticator = authenticator
+	}
+}
+
+func (a basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+func (a bearerAuth) Authenticate(req *http.Request) error {
+	token, err := a.provider(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (noAuth) Authenticate(req *http.Request) error {
+	return nil
+}
+
+// defaultAuthenticator is the Authenticator for the credentials given to NewClient.  Empty credentials mean
+// anonymous access.
+func defaultAuthenticator(username, password string) Authenticator {
+	if username == "" && password == "" {
+		return NoAuth()
+	}
+	return BasicAuth(username, password)
+}
@@ -0,0 +1,84 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func authServer(t *testing.T, wantAuthorization *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != *wantAuthorization {
+			t.Fatalf("Want Authorization %q but got %q\n", *wantAuthorization, r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+}
+
+func TestAPIToken(t *testing.T) {
+	want := "Basic dTp0b2tlbg=="
+	testServer := authServer(t, &want)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "", "", WithAuthenticator(APIToken("u", "token")))
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+}
+
+func TestAnonymous(t *testing.T) {
+	want := ""
+	testServer := authServer(t, &want)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	for _, jenkinsClient := range []Jenkins{NewClient(url, "", ""), NewClient(url, "u", "p", WithAuthenticator(NoAuth()))} {
+		if _, err := jenkinsClient.GetJobs(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+}
+
+func TestBearerTokenRotation(t *testing.T) {
+	var want string
+	testServer := authServer(t, &want)
+	defer testServer.Close()
+
+	var issued int
+	provider := func(ctx context.Context) (string, error) {
+		issued++
+		return fmt.Sprintf("token-%d", issued), nil
+	}
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "", "", WithAuthenticator(BearerToken(provider)))
+	for _, w := range []string{"Bearer token-1", "Bearer token-2"} {
+		want = w
+		if _, err := jenkinsClient.GetJobs(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+}
+
+func TestBearerTokenProviderError(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("Not expecting a request without a token\n")
+	}))
+	defer testServer.Close()
+
+	providerErr := errors.New("identity provider unavailable")
+	provider := func(ctx context.Context) (string, error) {
+		return "", providerErr
+	}
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "", "", WithAuthenticator(BearerToken(provider)))
+	if _, err := jenkinsClient.GetJobs(); !errors.Is(err, providerErr) {
+		t.Fatalf("Want the provider error but got %v\n", err)
+	}
+}
@@ -0,0 +1,219 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// buildJSON is a build as Jenkins encodes it, with times in milliseconds and causes and parameters among its
+// actions.
+type buildJSON struct {
+	Number            int    `json:"number"`
+	URL               string `json:"url"`
+	DisplayName       string `json:"displayName"`
+	Description       string `json:"description"`
+	QueueID           int64  `json:"queueId"`
+	Building          bool   `json:"building"`
+	Result            string `json:"result"`
+	Timestamp         int64  `json:"timestamp"`
+	Duration          int64  `json:"duration"`
+	EstimatedDuration int64  `json:"estimatedDuration"`
+	BuiltOn           string `json:"builtOn"`
+	Actions           []struct {
+		Causes []struct {
+			ShortDescription string `json:"shortDescription"`
+			UserID           string `json:"userId"`
+			UserName         string `json:"userName"`
+			UpstreamProject  string `json:"upstreamProject"`
+			UpstreamBuild    int    `json:"upstreamBuild"`
+			UpstreamURL      string `json:"upstreamUrl"`
+		} `json:"causes"`
+		Parameters []struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		} `json:"parameters"`
+	} `json:"actions"`
+	Artifacts []struct {
+		FileName     string `json:"fileName"`
+		RelativePath string `json:"relativePath"`
+	} `json:"artifacts"`
+	ChangeSet  changeSetJSON   `json:"changeSet"`  // freestyle and Maven builds
+	ChangeSets []changeSetJSON `json:"changeSets"` // Pipeline builds, one per checkout
+}
+
+// buildFields are the fields of a build that buildJSON decodes.  Asking for just these keeps Jenkins from
+// sending the rest, such as the actions of plugins the client does not model.
+const buildFields = "number,url,displayName,description,queueId,building,result,timestamp,duration,estimatedDuration,builtOn," +
+	"actions[causes[shortDescription,userId,userName,upstreamProject,upstreamBuild,upstreamUrl],parameters[name,value]]," +
+	"artifacts[fileName,relativePath]," +
+	"changeSet[" + changeSetFields + "],changeSets[" + changeSetFields + "]"
+
+const changeSetFields = "items[commitId,msg,timestamp,author[fullName],affectedPaths]"
+
+type changeSetJSON struct {
+	Items []struct {
+		CommitID  string `json:"commitId"`
+		Message   string `json:"msg"`
+		Timestamp int64  `json:"timestamp"`
+		Author    struct {
+			FullName string `json:"fullName"`
+		} `json:"author"`
+		AffectedPaths []string `json:"affectedPaths"`
+	} `json:"items"`
+}
+
+func (b buildJSON) build() Build {
+	build := Build{
+		Number:            b.Number,
+		URL:               b.URL,
+		DisplayName:       b.DisplayName,
+		Description:       b.Description,
+		QueueID:           b.QueueID,
+		Building:          b.Building,
+		Result:            b.Result,
+		Timestamp:         time.UnixMilli(b.Timestamp),
+		Duration:          time.Duration(b.Duration) * time.Millisecond,
+		EstimatedDuration: time.Duration(b.EstimatedDuration) * time.Millisecond,
+		BuiltOn:           b.BuiltOn,
+	}
+	for _, action := range b.Actions {
+		for _, c := range action.Causes {
+			build.Causes = append(build.Causes, BuildCause(c))
+		}
+		for _, p := range action.Parameters {
+			build.Parameters = append(build.Parameters, BuildParameter{Name: p.Name, Value: parameterValue(p.Value)})
+		}
+	}
+	for _, a := range b.Artifacts {
+		build.Artifacts = append(build.Artifacts, Artifact{
+			FileName:     a.FileName,
+			RelativePath: a.RelativePath,
+			URL:          strings.TrimSuffix(b.URL, "/") + "/artifact/" + escapePath(a.RelativePath),
+		})
+	}
+	for _, changeSet := range append([]changeSetJSON{b.ChangeSet}, b.ChangeSets...) {
+		for _, item := range changeSet.Items {
+			build.ChangeSet = append(build.ChangeSet, Change{
+				CommitID:      item.CommitID,
+				Message:       item.Message,
+				Author:        item.Author.FullName,
+				Timestamp:     time.UnixMilli(item.Timestamp),
+				AffectedPaths: item.AffectedPaths,
+			})
+		}
+	}
+	return build
+}
+
+// escapePath escapes each segment of a slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// parameterValue returns the value of a build parameter as a string: strings as they are, other JSON values in
+// their encoding, and "" for parameters without a value.
+func parameterValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// GetBuild is GetBuildContext with a background context.
+func (client Client) GetBuild(jobName string, number int) (Build, error) {
+	return client.GetBuildContext(context.Background(), jobName, number)
+}
+
+// GetBuildContext retrieves the build with the given number of the named job.
+func (client Client) GetBuildContext(ctx context.Context, jobName string, number int) (Build, error) {
+	return client.getBuild(ctx, jobName, strconv.Itoa(number), buildFields)
+}
+
+// getBuild retrieves a build of the named job.  ref is the number of the build or a permalink such as lastBuild.
+// tree names the fields Jenkins sends; the others are left zero.
+func (client Client) getBuild(ctx context.Context, jobName, ref, tree string) (Build, error) {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return Build{}, err
+	}
+	var data []byte
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(jobName, ref+"/api/json", url.Values{"tree": {tree}}), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
+		}
+		return nil
+	}
+	if err := client.try(ctx, work); err != nil {
+		return Build{}, err
+	}
+
+	var b buildJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Build{}, err
+	}
+	return b.build(), nil
+}
+
+// WaitForBuild polls the build with the given number of the named job until it has finished, and returns it with
+// its result.  Waiting ends with ctx's error when ctx is done.
+func (client Client) WaitForBuild(ctx context.Context, jobName string, number int, opts ...WaitOption) (Build, error) {
+	o := newWaitOptions(opts)
+	var build Build
+	err := o.poll(ctx, func() (bool, error) {
+		var err error
+		build, err = client.GetBuildContext(ctx, jobName, number)
+		if err != nil {
+			return false, err
+		}
+		// Jenkins clears building a moment before it records the result.
+		if !build.Building && build.Result != "" {
+			return true, nil
+		}
+		progress := BuildProgress{
+			Number:            build.Number,
+			Building:          build.Building,
+			Elapsed:           time.Since(build.Timestamp),
+			EstimatedDuration: build.EstimatedDuration,
+		}
+		client.log().LogAttrs(ctx, slog.LevelDebug, "waiting for Jenkins build",
+			slog.String("job", jobName),
+			slog.Int("number", number),
+			slog.Duration("elapsed", progress.Elapsed),
+			slog.Duration("estimated_duration", progress.EstimatedDuration),
+		)
+		if o.buildProgress != nil {
+			o.buildProgress(progress)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return Build{}, err
+	}
+	return build, nil
+}
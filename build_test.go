@@ -0,0 +1,177 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestWaitForBuild(t *testing.T) {
+	started := time.Now().Add(-time.Minute).UnixMilli()
+	polls := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/team/job/service/17/api/json" {
+			t.Fatalf("wanted URL path /job/team/job/service/17/api/json but found %s\n", r.URL.Path)
+		}
+		polls++
+		switch polls {
+		case 1:
+			fmt.Fprintf(w, `{"number":17,"building":true,"result":null,"timestamp":%d,"estimatedDuration":120000}`, started)
+		case 2:
+			fmt.Fprintf(w, `{"number":17,"building":false,"result":null,"timestamp":%d,"estimatedDuration":120000}`, started)
+		default:
+			fmt.Fprintf(w, `{"number":17,"url":"http://jenkins/job/team/job/service/17/","building":false,"result":"SUCCESS","timestamp":%d,"duration":90000,"estimatedDuration":120000,"queueId":42}`, started)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	var progress []BuildProgress
+	build, err := jenkinsClient.WaitForBuild(context.Background(), "team/service", 17, fastPolls, WithBuildProgress(func(p BuildProgress) {
+		progress = append(progress, p)
+	}))
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if build.Result != "SUCCESS" || build.Duration != 90*time.Second || build.QueueID != 42 || build.Timestamp.UnixMilli() != started {
+		t.Fatalf("unexpected build %+v\n", build)
+	}
+	if len(progress) != 2 {
+		t.Fatalf("want 2 progress reports but got %d\n", len(progress))
+	}
+	if !progress[0].Building || progress[0].EstimatedDuration != 2*time.Minute || progress[0].Elapsed < time.Minute {
+		t.Fatalf("unexpected progress %+v\n", progress[0])
+	}
+}
+
+func TestWaitForBuildContextDone(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"number":17,"building":true}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := jenkinsClient.WaitForBuild(ctx, "service", 17, fastPolls); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded but got %v\n", err)
+	}
+}
+
+func TestWaitForBuildNotFound(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if _, err := jenkinsClient.WaitForBuild(context.Background(), "service", 17, fastPolls); !IsNotFound(err) {
+		t.Fatalf("want a not-found error but got %v\n", err)
+	}
+}
+
+var buildJSONDocument = `{
+  "_class": "hudson.model.FreeStyleBuild",
+  "actions": [
+    {"_class": "hudson.model.ParametersAction", "parameters": [
+      {"_class": "hudson.model.StringParameterValue", "name": "BRANCH", "value": "main"},
+      {"_class": "hudson.model.BooleanParameterValue", "name": "DEPLOY", "value": true},
+      {"_class": "hudson.model.FileParameterValue", "name": "settings.xml"}
+    ]},
+    {"_class": "hudson.model.CauseAction", "causes": [
+      {"_class": "hudson.model.Cause$UpstreamCause", "shortDescription": "Started by upstream project \"release\" build number 3", "upstreamBuild": 3, "upstreamProject": "release", "upstreamUrl": "job/release/"}
+    ]},
+    {}
+  ],
+  "artifacts": [
+    {"displayPath": "app.jar", "fileName": "app.jar", "relativePath": "target/app.jar"},
+    {"displayPath": "release notes.txt", "fileName": "release notes.txt", "relativePath": "release notes.txt"}
+  ],
+  "building": false,
+  "description": "nightly",
+  "displayName": "#17",
+  "duration": 90000,
+  "estimatedDuration": 120000,
+  "number": 17,
+  "queueId": 42,
+  "result": "UNSTABLE",
+  "timestamp": 1456425493292,
+  "url": "https://server/job/service/17/",
+  "builtOn": "linux-agent-1",
+  "changeSet": {
+    "_class": "hudson.plugins.git.GitChangeSetList",
+    "items": [
+      {"affectedPaths": ["pom.xml"], "commitId": "3f5a9c", "timestamp": 1456425000000, "author": {"fullName": "Jane Doe"}, "msg": "Bump version"}
+    ],
+    "kind": "git"
+  }
+}`
+
+func TestGetBuild(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/service/17/api/json" {
+			t.Fatalf("wanted URL path /job/service/17/api/json but found %s\n", r.URL.Path)
+		}
+		if tree := r.URL.Query().Get("tree"); tree != buildFields {
+			t.Fatalf("wanted tree %s but found %s\n", buildFields, tree)
+		}
+		fmt.Fprintln(w, buildJSONDocument)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	build, err := jenkinsClient.GetBuild("service", 17)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if build.Number != 17 || build.Result != "UNSTABLE" || build.Building || build.DisplayName != "#17" || build.Description != "nightly" || build.BuiltOn != "linux-agent-1" {
+		t.Fatalf("unexpected build %+v\n", build)
+	}
+	if !build.Timestamp.Equal(time.UnixMilli(1456425493292)) || build.Duration != 90*time.Second || build.EstimatedDuration != 2*time.Minute {
+		t.Fatalf("unexpected build times %+v\n", build)
+	}
+	wantParameters := []BuildParameter{{"BRANCH", "main"}, {"DEPLOY", "true"}, {"settings.xml", ""}}
+	if len(build.Parameters) != len(wantParameters) {
+		t.Fatalf("want parameters %v but got %v\n", wantParameters, build.Parameters)
+	}
+	for i, p := range wantParameters {
+		if build.Parameters[i] != p {
+			t.Fatalf("want parameters %v but got %v\n", wantParameters, build.Parameters)
+		}
+	}
+	if len(build.Causes) != 1 || build.Causes[0].UpstreamProject != "release" || build.Causes[0].UpstreamBuild != 3 {
+		t.Fatalf("unexpected causes %+v\n", build.Causes)
+	}
+	if len(build.Artifacts) != 2 || build.Artifacts[1].URL != "https://server/job/service/17/artifact/release%20notes.txt" {
+		t.Fatalf("unexpected artifacts %+v\n", build.Artifacts)
+	}
+	if len(build.ChangeSet) != 1 || build.ChangeSet[0].Author != "Jane Doe" || build.ChangeSet[0].Message != "Bump version" || build.ChangeSet[0].AffectedPaths[0] != "pom.xml" {
+		t.Fatalf("unexpected change set %+v\n", build.ChangeSet)
+	}
+}
+
+func TestGetBuildPipelineChangeSets(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"number":3,"changeSets":[{"items":[{"commitId":"a"}]},{"items":[{"commitId":"b"},{"commitId":"c"}]}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	build, err := jenkinsClient.GetBuild("pipeline", 3)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if len(build.ChangeSet) != 3 || build.ChangeSet[2].CommitID != "c" {
+		t.Fatalf("unexpected change set %+v\n", build.ChangeSet)
+	}
+}
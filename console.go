@@ -0,0 +1,124 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetConsoleText is GetConsoleTextContext with a background context.
+func (client Client) GetConsoleText(jobName string, number int) (string, error) {
+	return client.GetConsoleTextContext(context.Background(), jobName, number)
+}
+
+// GetConsoleTextContext retrieves the console output of the build with the given number of the named job, as far
+// as it has been written.  Use StreamConsole to follow a running build.
+func (client Client) GetConsoleTextContext(ctx context.Context, jobName string, number int) (string, error) {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return "", err
+	}
+	var data []byte
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(jobName, strconv.Itoa(number)+"/consoleText", nil), nil)
+		if err != nil {
+			return err
+		}
+
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
+		}
+		return nil
+	}
+	if err := client.try(ctx, work); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// StreamConsole writes the console output of the build with the given number of the named job to w as the build
+// produces it, and returns once the build has finished and all of its output is written.  Each request asks
+// only for the output after what was already written.  Polls are spaced as configured by opts, starting again
+// from the initial interval whenever new output arrives.  Streaming ends with ctx's error when ctx is done.
+func (client Client) StreamConsole(ctx context.Context, jobName string, number int, w io.Writer, opts ...WaitOption) error {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return err
+	}
+	o := newWaitOptions(opts)
+	interval := o.pollInterval
+	var start int64
+	for {
+		chunk, next, more, err := client.progressiveText(ctx, jobName, number, start)
+		if err != nil {
+			return err
+		}
+		if len(chunk) > 0 {
+			if _, err := w.Write(chunk); err != nil {
+				return err
+			}
+		}
+		if !more {
+			return nil
+		}
+
+		if next > start {
+			interval = o.pollInterval
+		} else {
+			interval *= 2
+			if interval > o.maxPollInterval {
+				interval = o.maxPollInterval
+			}
+		}
+		start = next
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// progressiveText retrieves the console output of a build from offset start.  It returns the output, the offset to
+// continue from, and whether Jenkins may have more output to come.
+func (client Client) progressiveText(ctx context.Context, jobName string, number int, start int64) ([]byte, int64, bool, error) {
+	var (
+		data []byte
+		next int64
+		more bool
+	)
+	resource := strconv.Itoa(number) + "/logText/progressiveText"
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(jobName, resource, url.Values{"start": {strconv.FormatInt(start, 10)}}), nil)
+		if err != nil {
+			return err
+		}
+
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
+		}
+		next, err = strconv.ParseInt(response.Header.Get("X-Text-Size"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("jenkins: console of %s #%d: bad X-Text-Size header: %w", jobName, number, err)
+		}
+		more = response.Header.Get("X-More-Data") == "true"
+		return nil
+	}
+	if err := client.try(ctx, work); err != nil {
+		return nil, 0, false, err
+	}
+	return data, next, more, nil
+}
@@ -0,0 +1,102 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetConsoleText(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/team/job/service/17/consoleText" {
+			t.Fatalf("wanted URL path /job/team/job/service/17/consoleText but found %s\n", r.URL.Path)
+		}
+		fmt.Fprint(w, "Started by user admin\nFinished: SUCCESS\n")
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	text, err := jenkinsClient.GetConsoleText("team/service", 17)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if text != "Started by user admin\nFinished: SUCCESS\n" {
+		t.Fatalf("unexpected console text %q\n", text)
+	}
+}
+
+func TestStreamConsole(t *testing.T) {
+	// The log grows by one line per request until the build finishes.
+	lines := []string{"Started by user admin\n", "Building...\n", "", "Finished: SUCCESS\n"}
+	var log string
+	requests := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/service/17/logText/progressiveText" {
+			t.Fatalf("wanted URL path /job/service/17/logText/progressiveText but found %s\n", r.URL.Path)
+		}
+		start, err := strconv.Atoi(r.URL.Query().Get("start"))
+		if err != nil || start > len(log) {
+			t.Fatalf("unexpected start %s\n", r.URL.RawQuery)
+		}
+		log += lines[requests]
+		requests++
+		w.Header().Set("X-Text-Size", strconv.Itoa(len(log)))
+		if requests < len(lines) {
+			w.Header().Set("X-More-Data", "true")
+		}
+		fmt.Fprint(w, log[start:])
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	var out bytes.Buffer
+	if err := jenkinsClient.StreamConsole(context.Background(), "service", 17, &out, fastPolls); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if out.String() != "Started by user admin\nBuilding...\nFinished: SUCCESS\n" {
+		t.Fatalf("unexpected console output %q\n", out.String())
+	}
+	if requests != len(lines) {
+		t.Fatalf("want %d requests but got %d\n", len(lines), requests)
+	}
+}
+
+func TestStreamConsoleContextDone(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Text-Size", "0")
+		w.Header().Set("X-More-Data", "true")
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+	if err := jenkinsClient.StreamConsole(ctx, "service", 17, &out, fastPolls); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded but got %v\n", err)
+	}
+}
+
+func TestStreamConsoleMissingTextSize(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "output")
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	var out bytes.Buffer
+	if err := jenkinsClient.StreamConsole(context.Background(), "service", 17, &out, fastPolls); err == nil {
+		t.Fatalf("want an error for a response without X-Text-Size\n")
+	}
+}
@@ -0,0 +1,69 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetJobsContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := jenkinsClient.GetJobsContext(ctx)
+	if err == nil {
+		t.Fatalf("Want an error from a hung server but got none\n")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Want context.DeadlineExceeded but got %v\n", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Want GetJobsContext to give up promptly but it took %v\n", elapsed)
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if _, err := jenkinsClient.GetJobConfigContext(ctx, "thejob"); err == nil {
+		t.Fatalf("Want an error but got none\n")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("Want 1 attempt after cancellation but got %d\n", n)
+	}
+}
+
+func TestConsumeResponseCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", "http://localhost:1/", nil)
+	if _, _, err := (Client{}).consumeResponse(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Want context.Canceled but got %v\n", err)
+	}
+}
@@ -0,0 +1,94 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CopyJob is CopyJobContext with a background context.
+func (client Client) CopyJob(srcJobName, dstJobName string) error {
+	return client.CopyJobContext(context.Background(), srcJobName, dstJobName)
+}
+
+// CopyJobContext creates the job dstJobName with a copy of the config of the job srcJobName.  The jobs may be in
+// different folders.  As with CreateJobContext, a failed attempt is only retried if the copy does not exist yet.
+func (client Client) CopyJobContext(ctx context.Context, srcJobName, dstJobName string) error {
+	ctx = withJob(ctx, dstJobName)
+	if err := checkJobPath(srcJobName); err != nil {
+		return err
+	}
+	if err := checkNewJobName(dstJobName); err != nil {
+		return err
+	}
+	dst := JobPath(dstJobName)
+	query := url.Values{
+		"name": {dst.Name()},
+		"mode": {"copy"},
+		"from": {"/" + JobPath(srcJobName).String()},
+	}
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(string(dst.Parent()), "createItem", query), bytes.NewBuffer([]byte("")))
+		if err != nil {
+			return err
+		}
+
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusFound {
+			return newCreateItemError(req, response, data, dstJobName)
+		}
+		return nil
+	}
+	tookEffect := func() (bool, error) {
+		return client.JobExistsContext(ctx, dstJobName)
+	}
+	return client.tryNonIdempotent(ctx, work, tookEffect)
+}
+
+// RenameJob is RenameJobContext with a background context.
+func (client Client) RenameJob(oldJobName, newJobName string) error {
+	return client.RenameJobContext(context.Background(), oldJobName, newJobName)
+}
+
+// RenameJobContext renames the job oldJobName to newJobName.  newJobName is either the new name alone or the
+// full path of the job under its new name; a job cannot be moved to another folder by renaming it.  A failed
+// attempt is only retried if no job has the new name yet.
+func (client Client) RenameJobContext(ctx context.Context, oldJobName, newJobName string) error {
+	ctx = withJob(ctx, oldJobName)
+	if err := checkJobPath(oldJobName); err != nil {
+		return err
+	}
+	if err := checkNewJobName(newJobName); err != nil {
+		return err
+	}
+	parent := JobPath(oldJobName).Parent()
+	newPath := JobPath(newJobName)
+	if newPath.Parent() == "" {
+		newPath = parent.Child(newPath.Name())
+	} else if newPath.Parent() != parent {
+		return &InvalidJobNameError{Name: newJobName, Reason: "a job can only be renamed within its folder"}
+	}
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(oldJobName, "doRename", url.Values{"newName": {newPath.Name()}}), bytes.NewBuffer([]byte("")))
+		if err != nil {
+			return err
+		}
+
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusFound {
+			return newCreateItemError(req, response, data, oldJobName)
+		}
+		return nil
+	}
+	tookEffect := func() (bool, error) {
+		return client.JobExistsContext(ctx, newPath.String())
+	}
+	return client.tryNonIdempotent(ctx, work, tookEffect)
+}
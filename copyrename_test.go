@@ -0,0 +1,113 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestCopyJob(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		if r.URL.Path != "/job/team/createItem" {
+			t.Fatalf("wanted URL path /job/team/createItem but found %s\n", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("name") != "feature-x" || query.Get("mode") != "copy" || query.Get("from") != "/templates/service" {
+			t.Fatalf("unexpected query %s\n", r.URL.RawQuery)
+		}
+		w.Header().Add("Location", "http://localhost:55555/job/team/job/feature-x/")
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.CopyJob("templates/service", "team/feature-x"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+}
+
+func TestCopyJobConflict(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, "A job already exists with the name feature-x")
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.CopyJob("service", "feature-x"); !IsConflict(err) {
+		t.Fatalf("want a conflict error but got %v\n", err)
+	}
+}
+
+func TestCopyJobRetryFindsCopy(t *testing.T) {
+	posts := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST":
+			posts++
+			w.WriteHeader(http.StatusBadGateway)
+		case r.URL.Path == "/job/feature-x/api/json":
+			fmt.Fprintln(w, `{"name":"feature-x"}`)
+		default:
+			t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	if err := jenkinsClient.CopyJob("service", "feature-x"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if posts != 1 {
+		t.Fatalf("want 1 POST but got %d\n", posts)
+	}
+}
+
+func TestRenameJob(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		if r.URL.Path != "/job/team/job/old/doRename" {
+			t.Fatalf("wanted URL path /job/team/job/old/doRename but found %s\n", r.URL.Path)
+		}
+		if r.URL.Query().Get("newName") != "new" {
+			t.Fatalf("want newName=new but got %s\n", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.RenameJob("team/old", "new"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if err := jenkinsClient.RenameJob("team/old", "team/new"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+}
+
+func TestRenameJobRejectsMove(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	for _, newName := range []string{"other/new", "bad:name"} {
+		err := jenkinsClient.RenameJob("team/old", newName)
+		if _, ok := err.(*InvalidJobNameError); !ok {
+			t.Fatalf("want an *InvalidJobNameError for %s but got %v\n", newName, err)
+		}
+	}
+}
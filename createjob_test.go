@@ -141,6 +141,11 @@ func TestCreateJenkinsJobsNoError(t *testing.T) {
 
 func TestCreateJenkinsJobs500(t *testing.T) {
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Before a failed create is retried, the client checks whether the job was created anyway.
+		if r.Method == "GET" && r.URL.Path == "/job/job-name/api/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
 		if r.Method != "POST" {
 			t.Fatalf("wanted POST but found %s\n", r.Method)
 		}
@@ -0,0 +1,180 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+type (
+	// crumb is a CSRF protection token issued by the Jenkins crumb issuer, together with the session cookies it
+	// is bound to.
+	crumb struct {
+		field   string
+		value   string
+		cookies []*http.Cookie
+	}
+
+	// crumbCache holds the crumb shared by all copies of a Client.  The mutex only guards the fields and is never
+	// held while a crumb is being fetched.
+	crumbCache struct {
+		mu       sync.Mutex
+		crumb    *crumb
+		fetching *crumbFetch
+	}
+
+	// crumbFetch is a fetch of a fresh crumb in progress.  done is closed once crumb or err is set.
+	crumbFetch struct {
+		done  chan struct{}
+		crumb *crumb
+		err   error
+	}
+
+	crumbResponse struct {
+		Crumb             string `json:"crumb"`
+		CrumbRequestField string `json:"crumbRequestField"`
+	}
+)
+
+// get returns the cached crumb, or nil if no crumb has been issued yet.
+func (cache *crumbCache) get() *crumb {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.crumb
+}
+
+// refresh replaces stale with a crumb obtained from fetch.  If another goroutine already replaced stale, its crumb
+// is returned instead of fetching again, and if another goroutine is fetching, its result is awaited.  Waiting
+// ends with ctx's error when ctx is done, so a hanging crumb issuer only holds up the request that asked it.
+func (cache *crumbCache) refresh(ctx context.Context, stale *crumb, fetch func() (*crumb, error)) (*crumb, error) {
+	for {
+		cache.mu.Lock()
+		if cache.crumb != nil && cache.crumb != stale {
+			c := cache.crumb
+			cache.mu.Unlock()
+			return c, nil
+		}
+		if inProgress := cache.fetching; inProgress != nil {
+			cache.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-inProgress.done:
+			}
+			// A fetch abandoned by its own request says nothing about this one, which fetches anew.
+			if inProgress.err != nil && !errors.Is(inProgress.err, context.Canceled) && !errors.Is(inProgress.err, context.DeadlineExceeded) {
+				return nil, inProgress.err
+			}
+			continue
+		}
+		f := &crumbFetch{done: make(chan struct{})}
+		cache.fetching = f
+		cache.mu.Unlock()
+
+		f.crumb, f.err = fetch()
+
+		cache.mu.Lock()
+		if f.err == nil {
+			cache.crumb = f.crumb
+		}
+		cache.fetching = nil
+		cache.mu.Unlock()
+		close(f.done)
+		return f.crumb, f.err
+	}
+}
+
+// apply attaches the crumb header and its session cookies to req.
+func (c *crumb) apply(req *http.Request) {
+	req.Header.Set(c.field, c.value)
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+}
+
+// crumbRejection is the message of the 403 Jenkins answers a request without a valid crumb with.  Other error pages
+// mention crumbs too, in the data-crumb-header attribute of their <head>, so only the message itself is matched.
+var crumbRejection = []byte("No valid crumb was included in the request")
+
+// isCrumbRejection reports whether Jenkins refused a request because its crumb was missing or stale.
+func isCrumbRejection(statusCode int, body []byte) bool {
+	return statusCode == http.StatusForbidden && bytes.Contains(body, crumbRejection)
+}
+
+// consumeWithCrumb performs a state-changing request.  Jenkins instances with CSRF protection enabled reject
+// such requests unless they carry a crumb, so the cached crumb is attached when there is one.  A missing or stale
+// crumb is answered with a 403, in which case a fresh crumb is fetched and the request is sent once more.  If no
+// crumb can be fetched, the 403 is returned.  Instances without CSRF protection never trigger a fetch.
+func (client Client) consumeWithCrumb(req *http.Request) (*http.Response, []byte, error) {
+	pristine := req.Clone(req.Context())
+	current := client.crumbs.get()
+	if current != nil {
+		current.apply(req)
+	}
+
+	response, data, err := client.send(req)
+	if err != nil || !isCrumbRejection(response.StatusCode, data) {
+		return response, data, err
+	}
+
+	retry, err := rewind(pristine)
+	if err != nil {
+		return response, data, nil
+	}
+	fresh, err := client.crumbs.refresh(req.Context(), current, func() (*crumb, error) {
+		return client.fetchCrumb(req)
+	})
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		// The request's own 403 says more about it than the crumb issuer's failure does.
+		return response, data, nil
+	}
+	fresh.apply(retry)
+	return client.send(retry)
+}
+
+// fetchCrumb asks the crumb issuer for a crumb valid for requests like req.
+func (client Client) fetchCrumb(req *http.Request) (*crumb, error) {
+	crumbReq, err := http.NewRequestWithContext(req.Context(), "GET", client.resourceURL("", "crumbIssuer/api/json", nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	crumbReq.Header.Set("Accept", "application/json")
+
+	response, data, err := client.send(crumbReq)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, newAPIError(crumbReq, response, data, "")
+	}
+
+	var issued crumbResponse
+	if err := json.Unmarshal(data, &issued); err != nil {
+		return nil, err
+	}
+	return &crumb{field: issued.CrumbRequestField, value: issued.Crumb, cookies: response.Cookies()}, nil
+}
+
+// rewind returns a copy of req that can be sent again, with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body of %s %s cannot be replayed", req.Method, req.URL)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry.Body = body
+	return retry, nil
+}
@@ -0,0 +1,174 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// crumbServer emulates a Jenkins instance with CSRF protection.  Each crumb is bound to a session cookie, and
+// a crumb is only good for maxUses POSTs.
+func crumbServer(t *testing.T, maxUses int, issued *int32) *httptest.Server {
+	var uses int
+	var current string
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/crumbIssuer/api/json" {
+			if r.Header.Get("Authorization") != "Basic dTpw" {
+				t.Fatalf("Want Basic dTpw but got %s\n", r.Header.Get("Authorization"))
+			}
+			n := atomic.AddInt32(issued, 1)
+			current = fmt.Sprintf("crumb-%d", n)
+			uses = 0
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: current})
+			fmt.Fprintf(w, `{"_class":"hudson.security.csrf.DefaultCrumbIssuer","crumb":"%s","crumbRequestField":"Jenkins-Crumb"}`, current)
+			return
+		}
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		cookie, err := r.Cookie("JSESSIONID")
+		if current == "" || r.Header.Get("Jenkins-Crumb") != current || err != nil || cookie.Value != current || uses == maxUses {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<html><body>Error 403 No valid crumb was included in the request</body></html>"))
+			return
+		}
+		uses++
+		if r.URL.Path == "/createItem" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusFound)
+	}))
+}
+
+func TestCrumbFetchedAndCached(t *testing.T) {
+	var issued int32
+	testServer := crumbServer(t, 10, &issued)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.CreateJob("job-name", fooJob); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if err := jenkinsClient.DeleteJob("job-name"); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if issued != 1 {
+		t.Fatalf("Want 1 crumb issued but got %d\n", issued)
+	}
+}
+
+func TestStaleCrumbRefreshed(t *testing.T) {
+	var issued int32
+	testServer := crumbServer(t, 1, &issued)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.CreateJob("job-name", fooJob); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if err := jenkinsClient.CreateJob("job-name-2", fooJob); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if issued != 2 {
+		t.Fatalf("Want 2 crumbs issued but got %d\n", issued)
+	}
+}
+
+func TestForbiddenWithoutCrumbComplaint(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/crumbIssuer/api/json" {
+			t.Fatalf("Not expecting a crumb request for an ordinary 403\n")
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("u is missing the Job/Create permission"))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.CreateJob("job-name", fooJob); err == nil {
+		t.Fatalf("Want an error but got none\n")
+	}
+}
+
+func TestHangingCrumbFetchDoesNotBlockOtherRequests(t *testing.T) {
+	release := make(chan struct{})
+	fetching := make(chan struct{}, 1)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/crumbIssuer/api/json" {
+			fetching <- struct{}{}
+			<-release
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<html><body>Error 403 No valid crumb was included in the request</body></html>"))
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	go jenkinsClient.DeleteJob("job-name")
+	<-fetching
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := jenkinsClient.DeleteJobContext(ctx, "job-name-2"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Want context.DeadlineExceeded but got %v\n", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Want the request to give up at its deadline but it took %v\n", elapsed)
+	}
+}
+
+func TestPermissionPageIsNotCrumbRejection(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/crumbIssuer/api/json" {
+			t.Fatalf("Not expecting a crumb request for a permission 403\n")
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<html><head data-crumb-header="Jenkins-Crumb" data-crumb-value="abc"></head><body>u is missing the Job/Delete permission</body></html>`))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.DeleteJob("job-name"); !IsForbidden(err) {
+		t.Fatalf("Want a forbidden error but got %v\n", err)
+	}
+}
+
+func TestFailedCrumbFetchReturnsRejection(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/crumbIssuer/api/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<html><body>Error 403 No valid crumb was included in the request</body></html>"))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	err := jenkinsClient.DeleteJob("job-name")
+	if !IsForbidden(err) || IsNotFound(err) {
+		t.Fatalf("Want the request's forbidden error but got %v\n", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || strings.Contains(apiErr.URL, "crumbIssuer") {
+		t.Fatalf("Want the error of the request, not of the crumb issuer, but got %v\n", err)
+	}
+}
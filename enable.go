@@ -0,0 +1,53 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+)
+
+// EnableJob is EnableJobContext with a background context.
+func (client Client) EnableJob(jobName string) error {
+	return client.EnableJobContext(context.Background(), jobName)
+}
+
+// EnableJobContext enables the named job, so that it builds again.  Enabling an enabled job does nothing.
+func (client Client) EnableJobContext(ctx context.Context, jobName string) error {
+	return client.setJobEnabled(ctx, jobName, "enable")
+}
+
+// DisableJob is DisableJobContext with a background context.
+func (client Client) DisableJob(jobName string) error {
+	return client.DisableJobContext(context.Background(), jobName)
+}
+
+// DisableJobContext disables the named job, so that it does not build until it is enabled again.  Disabling a
+// disabled job does nothing.
+func (client Client) DisableJobContext(ctx context.Context, jobName string) error {
+	return client.setJobEnabled(ctx, jobName, "disable")
+}
+
+// setJobEnabled posts to the enable or disable action of the named job.  Both are idempotent, so failed attempts
+// are retried.
+func (client Client) setJobEnabled(ctx context.Context, jobName, action string) error {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return err
+	}
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(jobName, action, nil), bytes.NewBuffer([]byte("")))
+		if err != nil {
+			return err
+		}
+
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusFound && response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
+		}
+		return nil
+	}
+	return client.try(ctx, work)
+}
@@ -0,0 +1,45 @@
+package jenkins
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestEnableDisableJob(t *testing.T) {
+	var paths []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.DisableJob("team/service"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if err := jenkinsClient.EnableJob("team/service"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if len(paths) != 2 || paths[0] != "/job/team/job/service/disable" || paths[1] != "/job/team/job/service/enable" {
+		t.Fatalf("want disable then enable but got %v\n", paths)
+	}
+}
+
+func TestDisableJobNotFound(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.DisableJob("service"); !IsNotFound(err) {
+		t.Fatalf("want a not-found error but got %v\n", err)
+	}
+}
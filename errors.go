@@ -0,0 +1,157 @@
+package jenkins
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBodyLength bounds how much of a response body an APIError keeps.  Jenkins error pages are large HTML
+// documents.
+const maxErrorBodyLength = 512
+
+var (
+	// ErrNotFound matches errors for jobs, builds or other resources that do not exist.
+	ErrNotFound = errors.New("jenkins: not found")
+
+	// ErrUnauthorized matches errors for requests Jenkins could not authenticate.
+	ErrUnauthorized = errors.New("jenkins: unauthorized")
+
+	// ErrForbidden matches errors for requests the authenticated user lacks the permission for.
+	ErrForbidden = errors.New("jenkins: forbidden")
+
+	// ErrConflict matches errors for creating a job that already exists.
+	ErrConflict = errors.New("jenkins: already exists")
+
+	// ErrUnknownJobType matches errors for jobs of a type that cannot be summarized.
+	ErrUnknownJobType = errors.New("jenkins: unhandled job type")
+
+	// ErrBuildMayHaveBeenQueued matches errors for trigger requests that failed in a way that leaves open whether
+	// Jenkins queued the build.  Such requests are not retried, so that the build is not queued twice.
+	ErrBuildMayHaveBeenQueued = errors.New("jenkins: build may have been queued")
+
+	// ErrQueueItemCancelled matches errors for queued builds that were cancelled before they started.
+	ErrQueueItemCancelled = errors.New("jenkins: queue item cancelled")
+)
+
+// APIError is returned when Jenkins answers a request with an unexpected HTTP status.  Use errors.Is with
+// ErrNotFound, ErrUnauthorized, ErrForbidden or ErrConflict, or the Is* helpers, to classify it.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	JobName    string        // empty for requests not about a particular job
+	Body       string        // at most maxErrorBodyLength bytes of the response body
+	RetryAfter time.Duration // how long the server asked the client to wait, from the Retry-After header
+
+	kind error
+}
+
+// newAPIError describes the unexpected response, with the given body, to req.
+func newAPIError(req *http.Request, response *http.Response, body []byte, jobName string) *APIError {
+	e := &APIError{
+		StatusCode: response.StatusCode,
+		Method:     req.Method,
+		URL:        redactURL(req.URL),
+		JobName:    jobName,
+		Body:       truncateBody(body),
+		RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+	}
+	switch response.StatusCode {
+	case http.StatusNotFound:
+		e.kind = ErrNotFound
+	case http.StatusUnauthorized:
+		e.kind = ErrUnauthorized
+	case http.StatusForbidden:
+		e.kind = ErrForbidden
+	case http.StatusConflict:
+		e.kind = ErrConflict
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "jenkins: %s %s", e.Method, e.URL)
+	if e.JobName != "" {
+		fmt.Fprintf(&b, " (job %s)", e.JobName)
+	}
+	fmt.Fprintf(&b, ": status code %d", e.StatusCode)
+	if e.Body != "" {
+		fmt.Fprintf(&b, ", response=%s", e.Body)
+	}
+	return b.String()
+}
+
+// Is reports whether target is the sentinel error that classifies e.
+func (e *APIError) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+// IsNotFound reports whether err means the requested job or resource does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err means Jenkins rejected the client's credentials.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err means the authenticated user lacks a permission.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsConflict reports whether err means a job that was to be created already exists.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// truncateBody returns at most maxErrorBodyLength bytes of body as valid UTF-8.
+func truncateBody(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) <= maxErrorBodyLength {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxErrorBodyLength], "") + "..."
+}
+
+// parseRetryAfter interprets a Retry-After header, which holds either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// newSummaryError attributes err, from making sense of the config of the named job, to the right phase.
+func newSummaryError(jobName string, err error) *JobSummaryError {
+	phase := PhaseParse
+	if errors.Is(err, ErrUnknownJobType) {
+		phase = PhaseUnknownType
+	}
+	return &JobSummaryError{JobName: jobName, Phase: phase, Err: err}
+}
+
+func (e JobSummaryError) Error() string {
+	if e.JobName == "" {
+		return fmt.Sprintf("Cannot summarize job (%s): %v", e.Phase, e.Err)
+	}
+	return fmt.Sprintf("Cannot summarize job %s (%s): %v", e.JobName, e.Phase, e.Err)
+}
+
+func (e JobSummaryError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,88 @@
+package jenkins
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGetJobConfigNotFound(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<html>" + strings.Repeat("Not found. ", 200) + "</html>"))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	_, err := jenkinsClient.GetJobConfig("thejob")
+	if !IsNotFound(err) {
+		t.Fatalf("Want a not-found error but got %v\n", err)
+	}
+	if IsUnauthorized(err) || IsConflict(err) {
+		t.Fatalf("Want only a not-found error but got %v\n", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Want an *APIError but got %T\n", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Want 404 but got %d\n", apiErr.StatusCode)
+	}
+	if apiErr.Method != "GET" {
+		t.Fatalf("Want GET but got %s\n", apiErr.Method)
+	}
+	if apiErr.URL != testServer.URL+"/job/thejob/config.xml" {
+		t.Fatalf("Want %s/job/thejob/config.xml but got %s\n", testServer.URL, apiErr.URL)
+	}
+	if apiErr.JobName != "thejob" {
+		t.Fatalf("Want thejob but got %s\n", apiErr.JobName)
+	}
+	if len(apiErr.Body) > maxErrorBodyLength+len("...") {
+		t.Fatalf("Want body truncated to %d bytes but got %d\n", maxErrorBodyLength, len(apiErr.Body))
+	}
+}
+
+func TestGetJobsUnauthorized(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "wrong")
+	if _, err := jenkinsClient.GetJobs(); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Want ErrUnauthorized but got %v\n", err)
+	}
+}
+
+func TestCreateJobAlreadyExists(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Error", "A job already exists with the name ‘job-name’")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("<html><body>A job already exists with the name ‘job-name’</body></html>"))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	err := jenkinsClient.CreateJob("job-name", fooJob)
+	if !IsConflict(err) {
+		t.Fatalf("Want a conflict error but got %v\n", err)
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	body := strings.Repeat("é", maxErrorBodyLength)
+	truncated := truncateBody([]byte(body))
+	if !strings.HasSuffix(truncated, "...") {
+		t.Fatalf("Want truncated body to end in ... but got %s\n", truncated)
+	}
+	if !strings.HasPrefix(body, strings.TrimSuffix(truncated, "...")) {
+		t.Fatalf("Want truncated body to be a prefix of the original\n")
+	}
+}
@@ -0,0 +1,90 @@
+package jenkins
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type (
+	// Instrumentation observes the requests a client makes, for metrics or tracing.  Its methods are called
+	// synchronously from the goroutine making the request, possibly from many goroutines at once, and should
+	// return quickly.
+	Instrumentation interface {
+		// RequestStarted is called before a request is sent, after it has cleared the rate limiter.
+		RequestStarted(info RequestInfo)
+
+		// RequestFinished is called once the response body has been read or the request has failed.
+		RequestFinished(info RequestInfo, result RequestResult)
+
+		// RetryScheduled is called when a failed request will be retried after a backoff.
+		RetryScheduled(info RetryInfo)
+	}
+
+	// RequestInfo describes a request to Jenkins.
+	RequestInfo struct {
+		Method   string
+		Endpoint string // the URL path with job names and numbers replaced by placeholders, e.g. /job/:name/config.xml
+		URL      string // the full URL, with secrets redacted
+		JobName  string // empty for requests not about a particular job
+		Attempt  int    // counting from 1
+	}
+
+	// RequestResult describes the outcome of a request to Jenkins.
+	RequestResult struct {
+		StatusCode int // zero if no response was received
+		BytesRead  int
+		Duration   time.Duration
+		Err        error // a transport error; unexpected statuses are not errors here
+	}
+
+	// RetryInfo describes a retry about to be made.
+	RetryInfo struct {
+		JobName string
+		Attempt int // the attempt that failed, counting from 1
+		Backoff time.Duration
+		Err     error
+	}
+)
+
+// WithInstrumentation makes the client report its requests to instrumentation.  See NewMetrics for a ready-made
+// implementation.
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(o *options) {
+		o.instrumentation = instrumentation
+	}
+}
+
+// numericSegment matches path segments that are build numbers or queue item ids.
+var numericSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// requestInfo describes req for the client's Instrumentation.
+func (client Client) requestInfo(req *http.Request) RequestInfo {
+	return RequestInfo{
+		Method:   req.Method,
+		Endpoint: client.endpoint(req),
+		URL:      redactURL(req.URL),
+		JobName:  jobFrom(req.Context()),
+		Attempt:  attemptFrom(req.Context()),
+	}
+}
+
+// endpoint returns the path of req relative to the Jenkins base URL, with the values that would make a metric
+// label unbounded replaced by placeholders.
+func (client Client) endpoint(req *http.Request) string {
+	path := req.URL.Path
+	if client.baseURL != nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(client.baseURL.Path, "/"))
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case i > 0 && segments[i-1] == "job":
+			segments[i] = ":name"
+		case numericSegment.MatchString(segment):
+			segments[i] = ":number"
+		}
+	}
+	return strings.Join(segments, "/")
+}
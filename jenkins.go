@@ -2,6 +2,7 @@ package jenkins
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -11,105 +12,253 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-
-	"github.com/ae6rt/retry"
+	"sync"
+	"time"
 )
 
+// Log is no longer written to by this package.
+//
+// Deprecated: give clients a structured logger with WithLogger.
 var Log *log.Logger = log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lshortfile)
 
-func NewClient(baseURL *url.URL, username, password string) Jenkins {
-	return Client{baseURL: baseURL, userName: username, password: password}
+// NewClient returns a Jenkins client for the server at baseURL, authenticating with username and password.  Empty
+// credentials make the client anonymous.  Use WithAuthenticator for other kinds of credentials.
+func NewClient(baseURL *url.URL, username, password string, opts ...Option) Jenkins {
+	o := options{
+		authenticator: defaultAuthenticator(username, password),
+		rateLimit:     DefaultRateLimit,
+		burst:         DefaultRateLimit,
+		maxInFlight:   DefaultMaxInFlight,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return Client{
+		baseURL:    baseURL,
+		auth:       o.authenticator,
+		httpClient: o.httpClient(),
+		userAgent:  o.userAgent,
+		crumbs:     &crumbCache{},
+		retry:      o.retryPolicy,
+		limiter:    newLimiter(o.rateLimit, o.burst, o.maxInFlight),
+
+		summaryWorkers: o.summaryWorkers,
+		logger:         o.logger,
+
+		instrumentation: o.instrumentation,
+	}
 }
 
+// GetJobSummariesFromFilesystem summarizes the jobs whose configs are found under root, the jobs directory of a
+// Jenkins installation.  Jobs whose summary cannot be determined are logged as warnings and left out.
 func (client Client) GetJobSummariesFromFilesystem(root string) ([]JobSummary, error) {
+	report, err := client.GetJobSummaryReportFromFilesystem(root, SummaryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range report.Errors {
+		client.log().Warn("skipping job that cannot be summarized", "job", e.JobName, "phase", string(e.Phase), "error", e.Err)
+	}
+	return report.Summaries, nil
+}
+
+// GetJobSummaryReportFromFilesystem summarizes the jobs whose configs are found under root, the jobs directory of a
+// Jenkins installation, and reports the jobs that could not be summarized alongside the summaries.
+func (client Client) GetJobSummaryReportFromFilesystem(root string, opts SummaryOptions) (JobSummaryReport, error) {
 	if exists, err := dirExists(root); err != nil || !exists {
 		if err != nil {
-			return nil, err
+			return JobSummaryReport{}, err
 		} else {
-			return nil, fmt.Errorf("jenkins.GetJobSummariesFromFilesystem: root directory %s of Jenkins jobs does not exist.\n", root)
+			return JobSummaryReport{}, fmt.Errorf("jenkins.GetJobSummariesFromFilesystem: root directory %s of Jenkins jobs does not exist.\n", root)
 		}
 	}
 
 	jobConfigFiles, err := findJobsInFilesystem(root)
 	if err != nil {
-		return nil, err
+		return JobSummaryReport{}, err
 	}
 
-	summaries := make([]JobSummary, 0)
+	report := JobSummaryReport{Summaries: make([]JobSummary, 0), Errors: make([]JobSummaryError, 0)}
 	for _, configFile := range jobConfigFiles {
-		jobName, err := jobNameFromConfigFileName(configFile)
+		jobSummary, err := summarizeConfigFile(configFile)
 		if err != nil {
-			Log.Printf("Cannot acquire job name from config file name %s: %v.  Skipping.\n", configFile, err)
+			summaryErr := err.(*JobSummaryError)
+			report.Errors = append(report.Errors, *summaryErr)
+			if opts.FailFast {
+				return report, summaryErr
+			}
 			continue
 		}
-		jobDescriptor := JobDescriptor{Name: jobName}
+		report.Summaries = append(report.Summaries, jobSummary)
+	}
+	return report, nil
+}
 
-		data, err := ioutil.ReadFile(configFile)
-		if err != nil {
-			Log.Printf("Cannot read config file %s: %v.  Skipping.\n", configFile, err)
-			continue
-		}
+// summarizeConfigFile summarizes the job whose config is in configFile.  Errors are of type *JobSummaryError.
+func summarizeConfigFile(configFile string) (JobSummary, error) {
+	jobName, err := jobNameFromConfigFileName(configFile)
+	if err != nil {
+		return JobSummary{}, &JobSummaryError{Phase: PhaseFetch, Err: fmt.Errorf("Cannot acquire job name from config file name %s: %v", configFile, err)}
+	}
 
-		jobSummary, err := getSummaryFromConfigBytes(data, jobDescriptor)
-		if err != nil {
-			Log.Printf("Cannot develop a sane job summary from config file data %s: %v.  Skipping.\n", configFile, err)
-			continue
-		}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return JobSummary{}, &JobSummaryError{JobName: jobName, Phase: PhaseFetch, Err: err}
+	}
 
-		summaries = append(summaries, jobSummary)
+	jobSummary, err := getSummaryFromConfigBytes(data, JobDescriptor{Name: jobName})
+	if err != nil {
+		return JobSummary{}, newSummaryError(jobName, err)
 	}
-	return summaries, nil
+	return jobSummary, nil
 }
 
+// GetJobSummaries is GetJobSummariesContext with a background context.
 func (client Client) GetJobSummaries() ([]JobSummary, error) {
-	if jobDescriptors, err := client.GetJobs(); err != nil {
+	return client.GetJobSummariesContext(context.Background())
+}
+
+// GetJobSummariesContext retrieves a summary of every job known to Jenkins, ordered by job name.  Jobs whose
+// summary cannot be determined are left out; use GetJobSummaryReport to learn which.
+func (client Client) GetJobSummariesContext(ctx context.Context) ([]JobSummary, error) {
+	report, err := client.GetJobSummaryReport(ctx, SummaryOptions{})
+	if err != nil {
 		return nil, err
-	} else {
-		summaries := make([]JobSummary, 0)
-		for _, jobDescriptor := range jobDescriptors {
-			if jobSummary, err := client.getJobSummary(jobDescriptor); err != nil {
-				continue
-			} else {
-				summaries = append(summaries, jobSummary)
+	}
+	return report.Summaries, nil
+}
+
+// GetJobSummaryReport retrieves a summary of every job known to Jenkins, ordered by job name, and reports the jobs
+// that could not be summarized alongside the summaries.  The job configs are fetched concurrently by a pool of
+// workers; see WithSummaryWorkers.
+func (client Client) GetJobSummaryReport(ctx context.Context, opts SummaryOptions) (JobSummaryReport, error) {
+	jobDescriptors, err := client.GetJobsContext(ctx)
+	if err != nil {
+		return JobSummaryReport{}, err
+	}
+
+	descriptors := make([]JobDescriptor, 0, len(jobDescriptors))
+	for _, jobDescriptor := range jobDescriptors {
+		descriptors = append(descriptors, jobDescriptor)
+	}
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].Name < descriptors[j].Name
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	summaries := make([]*JobSummary, len(descriptors))
+	errs := make([]*JobSummaryError, len(descriptors))
+	var failFastErr error
+	var once sync.Once
+	err = forEachConcurrently(ctx, len(descriptors), client.summaryWorkerCount(), func(ctx context.Context, i int) {
+		jobSummary, err := client.getJobSummary(ctx, descriptors[i])
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			errs[i] = err.(*JobSummaryError)
+			if opts.FailFast {
+				once.Do(func() {
+					failFastErr = errs[i]
+					cancel()
+				})
 			}
+			return
 		}
-		return summaries, nil
+		summaries[i] = &jobSummary
+	})
+
+	report := JobSummaryReport{Summaries: make([]JobSummary, 0, len(descriptors)), Errors: make([]JobSummaryError, 0)}
+	for i := range descriptors {
+		switch {
+		case errs[i] != nil:
+			report.Errors = append(report.Errors, *errs[i])
+		case summaries[i] != nil:
+			report.Summaries = append(report.Summaries, *summaries[i])
+		}
+	}
+	if failFastErr != nil {
+		return report, failFastErr
+	}
+	if err != nil {
+		return JobSummaryReport{}, err
 	}
+	return report, nil
 }
 
-func (client Client) getJobSummary(jobDescriptor JobDescriptor) (JobSummary, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
+// summaryWorkerCount returns the number of workers fetching job configs for GetJobSummaries.
+func (client Client) summaryWorkerCount() int {
+	if client.summaryWorkers < 1 {
+		return DefaultSummaryWorkers
+	}
+	return client.summaryWorkers
+}
+
+// forEachConcurrently calls work for each index in [0, n) from a pool of workers goroutines, and waits for them to
+// finish.  No further work is started once ctx is done, in which case ctx's error is returned.
+func forEachConcurrently(ctx context.Context, n, workers int, work func(ctx context.Context, i int)) error {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				work(ctx, i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return ctx.Err()
+}
 
+// getJobSummary summarizes the job described by jobDescriptor.  Errors are of type *JobSummaryError.
+func (client Client) getJobSummary(ctx context.Context, jobDescriptor JobDescriptor) (JobSummary, error) {
+	ctx = withJob(ctx, jobDescriptor.path())
+	if err := checkJobPath(jobDescriptor.path()); err != nil {
+		return JobSummary{}, &JobSummaryError{JobName: jobDescriptor.path(), Phase: PhaseFetch, Err: err}
+	}
 	var data []byte
-	work := func() error {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/job/%s/config.xml", client.baseURL.String(), jobDescriptor.Name), nil)
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(jobDescriptor.path(), "config.xml", nil), nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Accept", "application/xml")
-		req.SetBasicAuth(client.userName, client.password)
 
-		var responseCode int
-		responseCode, data, err = consumeResponse(req)
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
 		if err != nil {
 			return err
 		}
 
-		if responseCode != http.StatusOK {
-			Log.Printf("%s", string(data))
-			return fmt.Errorf("%s", string(data))
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobDescriptor.path())
 		}
 		return nil
 	}
 
-	if err := retry.Try(work); err != nil {
-		return JobSummary{}, err
+	if err := client.try(ctx, work); err != nil {
+		return JobSummary{}, &JobSummaryError{JobName: jobDescriptor.path(), Phase: PhaseFetch, Err: err}
 	}
 
 	summary, err := getSummaryFromConfigBytes(data, jobDescriptor)
 	if err != nil {
-		return JobSummary{}, err
+		return JobSummary{}, newSummaryError(jobDescriptor.path(), err)
 	}
 	return summary, nil
 }
@@ -122,34 +271,36 @@ func referencesSingleGitRepo(scmInfo Scm) bool {
 	return len(scmInfo.UserRemoteConfigs.UserRemoteConfig) == 1
 }
 
-// GetJobs retrieves the set of Jenkins jobs as a map indexed by job name.
+// GetJobs is GetJobsContext with a background context.
 func (client Client) GetJobs() (map[string]JobDescriptor, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
+	return client.GetJobsContext(context.Background())
+}
 
+// GetJobsContext retrieves the set of top-level Jenkins jobs as a map indexed by job name.  Use
+// GetJobsRecursiveContext to include the jobs inside folders.
+func (client Client) GetJobsContext(ctx context.Context) (map[string]JobDescriptor, error) {
 	var data []byte
-	work := func() error {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/json/jobs", client.baseURL.String()), nil)
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL("", "api/json/jobs", nil), nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Accept", "application/json")
-		req.SetBasicAuth(client.userName, client.password)
 
-		var responseCode int
-		responseCode, data, err = consumeResponse(req)
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
 		if err != nil {
 			return err
 		}
 
-		if responseCode != http.StatusOK {
-			Log.Printf("%s", string(data))
-			return fmt.Errorf("%s", string(data))
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, "")
 		}
 
 		return nil
 	}
 
-	if err := retry.Try(work); err != nil {
+	if err := client.try(ctx, work); err != nil {
 		return nil, err
 	}
 
@@ -160,129 +311,262 @@ func (client Client) GetJobs() (map[string]JobDescriptor, error) {
 
 	jobs := make(map[string]JobDescriptor)
 	for _, v := range t.Jobs {
+		v.Path = JobPath(v.Name)
 		jobs[v.Name] = v
 	}
 	return jobs, nil
 }
 
-// GetJobConfig retrieves the Jenkins jobs config for the named job.
+// GetJobConfig is GetJobConfigContext with a background context.
 func (client Client) GetJobConfig(jobName string) (JobConfig, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
+	return client.GetJobConfigContext(context.Background(), jobName)
+}
+
+// GetJobConfigContext retrieves the Jenkins jobs config for the named job.
+func (client Client) GetJobConfigContext(ctx context.Context, jobName string) (JobConfig, error) {
+	data, err := client.getJobConfigXML(ctx, jobName)
+	if err != nil {
+		return JobConfig{}, err
+	}
+
+	var config JobConfig
+	reader := bytes.NewBuffer(stripXMLDeclaration(data))
+	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
+		return JobConfig{}, err
+	}
+	config.JobName = jobName
+	return config, nil
+}
 
+// getJobConfigXML retrieves the config.xml document of the named job.
+func (client Client) getJobConfigXML(ctx context.Context, jobName string) ([]byte, error) {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return nil, err
+	}
 	var data []byte
-	work := func() error {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/job/%s/config.xml", client.baseURL.String(), jobName), nil)
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(jobName, "config.xml", nil), nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Accept", "application/xml")
-		req.SetBasicAuth(client.userName, client.password)
 
-		var responseCode int
-		responseCode, data, err = consumeResponse(req)
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
 		if err != nil {
 			return err
 		}
 
-		if responseCode != http.StatusOK {
-			Log.Printf("%s", string(data))
-			return fmt.Errorf("%s", string(data))
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
 		}
 		return nil
 	}
-	if err := retry.Try(work); err != nil {
-		return JobConfig{}, err
-	}
-
-	var config JobConfig
-	reader := bytes.NewBuffer(data)
-	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
-		return JobConfig{}, err
+	if err := client.try(ctx, work); err != nil {
+		return nil, err
 	}
-	config.JobName = jobName
-	return config, nil
+	return data, nil
 }
 
-// CreateJob creates a Jenkins job with the given name for the given XML job config.
+// CreateJob is CreateJobContext with a background context.
 func (client Client) CreateJob(jobName, jobConfigXML string) error {
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/createItem?name=%s", client.baseURL.String(), jobName), bytes.NewBuffer([]byte(jobConfigXML)))
-	if err != nil {
+	return client.CreateJobContext(context.Background(), jobName, jobConfigXML)
+}
+
+// CreateJobContext creates a Jenkins job with the given name for the given XML job config.  Names Jenkins would
+// refuse are rejected with an *InvalidJobNameError before any request is made.  Creating a job is not
+// idempotent, so before a failed attempt is retried the job is looked up: if it exists, an earlier attempt took
+// effect and CreateJobContext succeeds.
+func (client Client) CreateJobContext(ctx context.Context, jobName, jobConfigXML string) error {
+	ctx = withJob(ctx, jobName)
+	if err := checkNewJobName(jobName); err != nil {
 		return err
 	}
-	req.Header.Set("Content-type", "application/xml")
-	req.SetBasicAuth(client.userName, client.password)
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(string(JobPath(jobName).Parent()), "createItem", url.Values{"name": {JobPath(jobName).Name()}}), bytes.NewBuffer([]byte(jobConfigXML)))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-type", "application/xml")
 
-	responseCode, data, err := consumeResponse(req)
-	if err != nil {
-		return err
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusOK {
+			return newCreateItemError(req, response, data, jobName)
+		}
+		return nil
+	}
+	tookEffect := func() (bool, error) {
+		return client.JobExistsContext(ctx, jobName)
 	}
-	if responseCode != http.StatusOK {
-		return fmt.Errorf("Error creating Jenkins job.  Status code: %d, response=%s\n", responseCode, string(data))
+	return client.tryNonIdempotent(ctx, work, tookEffect)
+}
+
+// newCreateItemError is newAPIError for requests that give a job a new name.  Jenkins answers a name that is
+// already taken with a 400 rather than a 409, so it is recognized by its message.
+func newCreateItemError(req *http.Request, response *http.Response, body []byte, jobName string) *APIError {
+	apiErr := newAPIError(req, response, body, jobName)
+	if response.StatusCode == http.StatusBadRequest && bytes.Contains(body, []byte("already exists")) {
+		apiErr.kind = ErrConflict
 	}
-	return nil
+	return apiErr
 }
 
-// DeleteJob creates a Jenkins job with the given name for the given XML job config.
+// DeleteJob is DeleteJobContext with a background context.
 func (client Client) DeleteJob(jobName string) error {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
-	work := func() error {
-		req, err := http.NewRequest("POST", fmt.Sprintf("%s/job/%s/doDelete", client.baseURL.String(), jobName), bytes.NewBuffer([]byte("")))
+	return client.DeleteJobContext(context.Background(), jobName)
+}
+
+// DeleteJobContext deletes the Jenkins job with the given name.  A retried delete that finds the job gone counts
+// as success, since an earlier attempt must have removed it.
+func (client Client) DeleteJobContext(ctx context.Context, jobName string) error {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return err
+	}
+	attempts := 0
+	work := func(ctx context.Context) error {
+		attempts++
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(jobName, "doDelete", nil), bytes.NewBuffer([]byte("")))
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Content-type", "application/xml")
-		req.SetBasicAuth(client.userName, client.password)
 
-		responseCode, data, err := consumeResponse(req)
+		response, data, err := client.consumeResponse(req)
 		if err != nil {
 			return err
 		}
-		if responseCode != http.StatusFound {
-			return fmt.Errorf("Error deleting Jenkins job.  Status code: %d, response=%s\n", responseCode, string(data))
+		if response.StatusCode == http.StatusNotFound && attempts > 1 {
+			return nil
+		}
+		if response.StatusCode != http.StatusFound {
+			return newAPIError(req, response, data, jobName)
 		}
 		return nil
 	}
-	return retry.Try(work)
+	return client.try(ctx, work)
 }
 
-// GetLastBuild retrieves the last build by job name
-func (client Client) GetLastBuild(jobName string) (LastBuild, error) {
-	retry := retry.New(3, retry.DefaultBackoffFunc)
+// JobExists is JobExistsContext with a background context.
+func (client Client) JobExists(jobName string) (bool, error) {
+	return client.JobExistsContext(context.Background(), jobName)
+}
 
-	var data []byte
-	work := func() error {
-		req, err := http.NewRequest("GET", fmt.Sprintf("%s/job/%s/lastBuild/api/json?depth=1&tree=timestamp,result,url", client.baseURL.String(), jobName), nil)
+// JobExistsContext reports whether Jenkins knows the named job.  It asks for the name of the job only, so it is
+// cheap.  Jenkins answering 404 means the job does not exist; any other failure is returned as an error.
+func (client Client) JobExistsContext(ctx context.Context, jobName string) (bool, error) {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return false, err
+	}
+	var exists bool
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(jobName, "api/json", url.Values{"tree": {"name"}}), nil)
 		if err != nil {
 			return err
 		}
 		req.Header.Set("Accept", "application/json")
-		req.SetBasicAuth(client.userName, client.password)
 
-		var responseCode int
-		responseCode, data, err = consumeResponse(req)
+		response, data, err := client.consumeResponse(req)
 		if err != nil {
 			return err
 		}
-
-		if responseCode != http.StatusOK {
-			return fmt.Errorf("Status code: %d, response %s", responseCode, string(data))
+		switch response.StatusCode {
+		case http.StatusOK:
+			exists = true
+			return nil
+		case http.StatusNotFound:
+			exists = false
+			return nil
 		}
-		return nil
+		return newAPIError(req, response, data, jobName)
 	}
-	if err := retry.Try(work); err != nil {
-		return LastBuild{}, err
+	if err := client.try(ctx, work); err != nil {
+		return false, err
 	}
+	return exists, nil
+}
+
+// GetLastBuild is GetLastBuildContext with a background context.
+func (client Client) GetLastBuild(jobName string) (LastBuild, error) {
+	return client.GetLastBuildContext(context.Background(), jobName)
+}
 
-	var lastBuild LastBuild
-	if err := json.Unmarshal(data, &lastBuild); err != nil {
+// GetLastBuildContext retrieves the last build by job name.  Only the fields of LastBuild are fetched, which keeps
+// it cheap to call for many jobs.  See GetBuildContext for the full build record.
+func (client Client) GetLastBuildContext(ctx context.Context, jobName string) (LastBuild, error) {
+	build, err := client.getBuildByPermalink(ctx, jobName, PermalinkLastBuild, "timestamp,result,url")
+	if err != nil {
 		return LastBuild{}, err
 	}
-	return lastBuild, nil
+	return LastBuild{
+		Result:          build.Result,
+		TimestampMillis: build.Timestamp.UnixMilli(),
+		URL:             build.URL,
+	}, nil
 }
 
-func consumeResponse(req *http.Request) (int, []byte, error) {
-	var response *http.Response
-	var err error
+// consumeResponse performs req and reads the whole response body.  The request is abandoned as soon as the
+// request's context is done.  POST requests carry a CSRF crumb when the server demands one.
+func (client Client) consumeResponse(req *http.Request) (*http.Response, []byte, error) {
+	if req.Method == "POST" && client.crumbs != nil {
+		return client.consumeWithCrumb(req)
+	}
+	return client.send(req)
+}
+
+// send performs req and returns the response together with its fully read body.
+func (client Client) send(req *http.Request) (*http.Response, []byte, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, nil, err
+	}
+	if client.userAgent != "" {
+		req.Header.Set("User-Agent", client.userAgent)
+	}
+	if client.auth != nil {
+		if err := client.auth.Authenticate(req); err != nil {
+			return nil, nil, err
+		}
+	}
+	if client.limiter != nil {
+		release, err := client.limiter.acquire(req.Context())
+		if err != nil {
+			return nil, nil, err
+		}
+		defer release()
+	}
+
+	var info RequestInfo
+	if client.instrumentation != nil {
+		info = client.requestInfo(req)
+		client.instrumentation.RequestStarted(info)
+	}
+	start := time.Now()
+	response, data, err := client.roundTrip(req)
+	duration := time.Since(start)
+	client.logRequest(req, response, len(data), duration, err)
+	if client.instrumentation != nil {
+		result := RequestResult{BytesRead: len(data), Duration: duration, Err: err}
+		if response != nil {
+			result.StatusCode = response.StatusCode
+		}
+		client.instrumentation.RequestFinished(info, result)
+	}
+	return response, data, err
+}
+
+// roundTrip performs req without any of the bookkeeping done by send.
+func (client Client) roundTrip(req *http.Request) (*http.Response, []byte, error) {
+
+	httpClient := client.httpClient
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
 	/*
 	   $ curl -i -d "" http://jenkins.example.com:8080/job/somejob/doDelete
 	   HTTP/1.1 302 Found
@@ -290,24 +574,41 @@ func consumeResponse(req *http.Request) (int, []byte, error) {
 	   Content-Length: 0
 	   Server: Jetty(8.y.z-SNAPSHOT)
 	*/
-	// So 302 means it worked, but we don't want to follow the redirect.  Why use http.DefaultTransport.RoundTrip:
-	// http://stackoverflow.com/questions/14420222/query-url-without-redirect-in-go
-	response, err = http.DefaultTransport.RoundTrip(req)
-
+	// So 302 means it worked, but we don't want to follow the redirect.  The http.Client used here is configured
+	// with doNotFollowRedirects, so the 302 and its Location header come back to the caller untouched.
+	response, err := httpClient.Do(req)
 	if err != nil {
-		return 0, nil, err
+		// The URL may hold secrets such as a remote-trigger token, and the error ends up in logs.
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = redactURL(req.URL)
+		}
+		return nil, nil, err
 	}
+	defer response.Body.Close()
 
 	data, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return 0, nil, err
+		return nil, nil, err
 	}
-	defer response.Body.Close()
-	return response.StatusCode, data, nil
+	return response, data, nil
+}
+
+// stripXMLDeclaration returns the document without a leading <?xml ...?> declaration.  Jenkins writes XML 1.1
+// declarations, which encoding/xml refuses, so configs are decoded without theirs.
+func stripXMLDeclaration(document []byte) []byte {
+	trimmed := bytes.TrimSpace(document)
+	if !bytes.HasPrefix(trimmed, []byte("<?xml")) {
+		return document
+	}
+	end := bytes.Index(trimmed, []byte("?>"))
+	if end < 0 {
+		return document
+	}
+	return trimmed[end+len("?>"):]
 }
 
 func getJobType(xmlDocument []byte) (JobType, error) {
-	decoder := xml.NewDecoder(bytes.NewBuffer(xmlDocument))
+	decoder := xml.NewDecoder(bytes.NewBuffer(stripXMLDeclaration(xmlDocument)))
 
 	var t string
 	for {
@@ -346,8 +647,8 @@ func dirExists(dirPath string) (bool, error) {
 // Discard config.xml:  resides at root
 // Discard jobname/a/b/config.xml:  resides more than one level below root
 // @root is the jobs directory, with a typical value of something like /srv/jenkins/jobs
-func findJobsInFilesystem(root string) ([]string, error) {
-	files := make([]string, 0)
+func findJobsInFilesystem(root string) (files []string, walkErr error) {
+	files = make([]string, 0)
 	markFn := func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -371,9 +672,8 @@ func findJobsInFilesystem(root string) ([]string, error) {
 		return nil, err
 	}
 	defer func() {
-		err := os.Chdir(pwd)
-		if err != nil {
-			Log.Printf("jenkins.findJobsInFilesystem() cannot restore working directory to %s: %v\n", pwd, err)
+		if err := os.Chdir(pwd); err != nil && walkErr == nil {
+			files, walkErr = nil, fmt.Errorf("jenkins.findJobsInFilesystem() cannot restore working directory to %s: %v", pwd, err)
 		}
 	}()
 
@@ -392,6 +692,7 @@ func findJobsInFilesystem(root string) ([]string, error) {
 }
 
 func getSummaryFromConfigBytes(data []byte, jobDescriptor JobDescriptor) (JobSummary, error) {
+	data = stripXMLDeclaration(data)
 
 	jobType, err := getJobType(data)
 	if err != nil {
@@ -411,6 +712,7 @@ func getSummaryFromConfigBytes(data []byte, jobDescriptor JobDescriptor) (JobSum
 		return JobSummary{
 			JobType:       Maven,
 			JobDescriptor: jobDescriptor,
+			Disabled:      maven.Disabled,
 			GitURL:        "", // the use of this field is deprecated
 			Branch:        "", // the use of this field is deprecated
 		}, nil
@@ -424,11 +726,12 @@ func getSummaryFromConfigBytes(data []byte, jobDescriptor JobDescriptor) (JobSum
 		return JobSummary{
 			JobType:       Freestyle,
 			JobDescriptor: jobDescriptor,
+			Disabled:      freestyle.Disabled,
 			GitURL:        "", // the use of this field is deprecated
 			Branch:        "", // the use of this field is deprecated
 		}, nil
 	}
-	return JobSummary{}, fmt.Errorf("Unhandled job type for job name: %s\n", jobDescriptor)
+	return JobSummary{}, fmt.Errorf("%w for job name: %s", ErrUnknownJobType, jobDescriptor.Name)
 }
 
 // jobNameFromConfigFileName returns "jobname" from path1/path2/pathN/jobname/config.xml
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -194,3 +195,20 @@ func TestGetJobConfig500(t *testing.T) {
 	}
 
 }
+
+func TestGetJobConfigXML11(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Replace(strings.TrimSpace(jobConfig), "version='1.0'", "version='1.1'", 1))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	config, err := jenkinsClient.GetJobConfig("thejob")
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if config.RootModule.ArtifactID != "widge" {
+		t.Fatalf("Want widge but got %s\n", config.RootModule.ArtifactID)
+	}
+}
@@ -0,0 +1,38 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestJobExists(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tree") != "name" {
+			t.Fatalf("want tree=name but got %s\n", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/job/team/job/present/api/json":
+			fmt.Fprintln(w, `{"name":"present"}`)
+		case "/job/team/job/absent/api/json":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if exists, err := jenkinsClient.JobExists("team/present"); err != nil || !exists {
+		t.Fatalf("want job present to exist but got %v, %v\n", exists, err)
+	}
+	if exists, err := jenkinsClient.JobExists("team/absent"); err != nil || exists {
+		t.Fatalf("want job absent not to exist but got %v, %v\n", exists, err)
+	}
+	if _, err := jenkinsClient.JobExists("secret"); !IsForbidden(err) {
+		t.Fatalf("want a forbidden error but got %v\n", err)
+	}
+}
@@ -0,0 +1,149 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// JobPath is the full name of a job: the names of the folders containing it and the name of the job itself,
+// separated by "/", such as "team/service".  The path of a top-level job is just its name.  Jenkins does not allow
+// "/" in item names, so a JobPath is unambiguous.  Every job name taken by this package may be a JobPath.
+type JobPath string
+
+type (
+	// folderListing is the JSON of an item group, such as the Jenkins root or a folder.
+	folderListing struct {
+		Jobs []folderItem `json:"jobs"`
+	}
+
+	// folderItem is a JobDescriptor that also lists the items of folders.  For other items Jobs is nil.
+	folderItem struct {
+		JobDescriptor
+		Jobs *[]json.RawMessage `json:"jobs"`
+	}
+)
+
+// NewJobPath returns the path of the job named by the last of segments, inside the folders named by the others.
+func NewJobPath(segments ...string) JobPath {
+	return JobPath(strings.Join(segments, "/"))
+}
+
+// Segments returns the folder names and the job name making up the path.
+func (p JobPath) Segments() []string {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(string(p), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// Name returns the name of the job itself, without its folders.
+func (p JobPath) Name() string {
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return ""
+	}
+	return segments[len(segments)-1]
+}
+
+// Parent returns the path of the folder containing the job, which is empty for top-level jobs.
+func (p JobPath) Parent() JobPath {
+	segments := p.Segments()
+	if len(segments) == 0 {
+		return ""
+	}
+	return NewJobPath(segments[:len(segments)-1]...)
+}
+
+// Child returns the path of the item named name inside the folder at p.
+func (p JobPath) Child(name string) JobPath {
+	if len(p.Segments()) == 0 {
+		return JobPath(name)
+	}
+	return NewJobPath(append(p.Segments(), name)...)
+}
+
+func (p JobPath) String() string {
+	return strings.Join(p.Segments(), "/")
+}
+
+// urlPath returns the URL path of the job relative to the Jenkins root, such as /job/team/job/service, with each
+// segment escaped.
+func (p JobPath) urlPath() string {
+	var b strings.Builder
+	for _, segment := range p.Segments() {
+		b.WriteString("/job/")
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String()
+}
+
+// GetJobsRecursive is GetJobsRecursiveContext with a background context.
+func (client Client) GetJobsRecursive() (map[string]JobDescriptor, error) {
+	return client.GetJobsRecursiveContext(context.Background())
+}
+
+// GetJobsRecursiveContext retrieves every item in Jenkins, including the folders and the items inside them at
+// any depth, as a map indexed by the full path of each item.  Folders have Folder set.
+func (client Client) GetJobsRecursiveContext(ctx context.Context) (map[string]JobDescriptor, error) {
+	jobs := make(map[string]JobDescriptor)
+	if err := client.walkFolder(ctx, "", jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// walkFolder adds the items in the folder at path, and in its subfolders, to jobs.
+func (client Client) walkFolder(ctx context.Context, path JobPath, jobs map[string]JobDescriptor) error {
+	var data []byte
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(string(path), "api/json", url.Values{"tree": {"jobs[name,url,color,jobs[name]]"}}), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, string(path))
+		}
+		return nil
+	}
+	if err := client.try(withJob(ctx, string(path)), work); err != nil {
+		return err
+	}
+
+	var listing folderListing
+	if err := json.Unmarshal(data, &listing); err != nil {
+		return err
+	}
+	for _, item := range listing.Jobs {
+		descriptor := item.JobDescriptor
+		descriptor.Path = path.Child(descriptor.Name)
+		descriptor.Folder = item.Jobs != nil
+		jobs[descriptor.Path.String()] = descriptor
+		if descriptor.Folder {
+			if err := client.walkFolder(ctx, descriptor.Path, jobs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// path returns the full path of the job, falling back to its name for descriptors made without one.
+func (d JobDescriptor) path() string {
+	if d.Path != "" {
+		return d.Path.String()
+	}
+	return d.Name
+}
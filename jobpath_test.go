@@ -0,0 +1,112 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestJobPath(t *testing.T) {
+	p := JobPath("team/backend/service")
+	if p.Name() != "service" {
+		t.Fatalf("Want service but got %s\n", p.Name())
+	}
+	if p.Parent() != "team/backend" {
+		t.Fatalf("Want team/backend but got %s\n", p.Parent())
+	}
+	if p.Parent().Parent().Parent() != "" {
+		t.Fatalf("Want the parent of a top-level job to be empty but got %s\n", p.Parent().Parent().Parent())
+	}
+	if NewJobPath("team", "backend", "service") != p {
+		t.Fatalf("Want NewJobPath to join segments with /\n")
+	}
+	if JobPath("").Child("a").Child("b") != "a/b" {
+		t.Fatalf("Want a/b but got %s\n", JobPath("").Child("a").Child("b"))
+	}
+	if got := JobPath("team/my service").urlPath(); got != "/job/team/job/my%20service" {
+		t.Fatalf("Want /job/team/job/my%%20service but got %s\n", got)
+	}
+	if got := JobPath("/team/service/").String(); got != "team/service" {
+		t.Fatalf("Want team/service but got %s\n", got)
+	}
+}
+
+func TestGetJobConfigInFolder(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/team/job/service/config.xml" {
+			t.Fatalf("Want /job/team/job/service/config.xml but got %s\n", r.URL.Path)
+		}
+		fmt.Fprintln(w, jobConfig)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	cfg, err := jenkinsClient.GetJobConfig("team/service")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if cfg.JobName != "team/service" {
+		t.Fatalf("Want team/service but got %s\n", cfg.JobName)
+	}
+}
+
+func TestCreateJobInFolder(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/team/createItem" {
+			t.Fatalf("Want /job/team/createItem but got %s\n", r.URL.Path)
+		}
+		if r.URL.Query().Get("name") != "service" {
+			t.Fatalf("Want name=service but got %s\n", r.URL.Query().Get("name"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.CreateJob("team/service", fooJob); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+}
+
+func TestGetJobsRecursive(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tree") != "jobs[name,url,color,jobs[name]]" {
+			t.Fatalf("Want tree=jobs[name,url,color,jobs[name]] but got %s\n", r.URL.Query().Get("tree"))
+		}
+		switch r.URL.Path {
+		case "/api/json":
+			fmt.Fprintln(w, `{"jobs":[{"name":"top","color":"blue","url":"http://ci/job/top/"},{"name":"team","url":"http://ci/job/team/","jobs":[{"name":"service"},{"name":"libs"}]}]}`)
+		case "/job/team/api/json":
+			fmt.Fprintln(w, `{"jobs":[{"name":"service","color":"red","url":"http://ci/job/team/job/service/"},{"name":"libs","url":"http://ci/job/team/job/libs/","jobs":[]}]}`)
+		case "/job/team/job/libs/api/json":
+			fmt.Fprintln(w, `{"jobs":[]}`)
+		default:
+			t.Fatalf("Unexpected request for %s\n", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	jobs, err := jenkinsClient.GetJobsRecursive()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if len(jobs) != 4 {
+		t.Fatalf("Want 4 items but got %d: %v\n", len(jobs), jobs)
+	}
+	service, ok := jobs["team/service"]
+	if !ok {
+		t.Fatalf("Want team/service among the jobs but got %v\n", jobs)
+	}
+	if service.Name != "service" || service.Path != "team/service" || service.Color != "red" || service.Folder {
+		t.Fatalf("Want job service in folder team but got %+v\n", service)
+	}
+	if !jobs["team"].Folder || !jobs["team/libs"].Folder || jobs["top"].Folder {
+		t.Fatalf("Want team and team/libs to be folders and top not\n")
+	}
+}
@@ -1,12 +1,18 @@
 package jenkins
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var jobConfig1 string = `
@@ -281,8 +287,8 @@ func TestHttpMavenJobSummary(t *testing.T) {
 		}))
 
 		url, _ := url.Parse(testServer.URL)
-		jenkinsClient := Client{baseURL: url, userName: "u", password: "p"}
-		summary, err := jenkinsClient.getJobSummary(JobDescriptor{Name: "thejob"})
+		jenkinsClient := NewClient(url, "u", "p").(Client)
+		summary, err := jenkinsClient.getJobSummary(context.Background(), JobDescriptor{Name: "thejob"})
 		if err != nil {
 			t.Fatalf("Unexpected error: %v\n", err)
 		}
@@ -328,13 +334,31 @@ func TestHttpUnknownJobSummary(t *testing.T) {
 	defer testServer.Close()
 
 	url, _ := url.Parse(testServer.URL)
-	jenkinsClient := Client{baseURL: url, userName: "u", password: "p"}
-	_, err := jenkinsClient.getJobSummary(JobDescriptor{Name: "thejob"})
+	jenkinsClient := NewClient(url, "u", "p").(Client)
+	_, err := jenkinsClient.getJobSummary(context.Background(), JobDescriptor{Name: "thejob"})
 	if err == nil {
 		t.Fatalf("Expected error owing to unknown job type\n")
 	}
 }
 
+func TestHttpJobSummaryErrorNamesFullPath(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries)).(Client)
+	_, err := jenkinsClient.getJobSummary(context.Background(), JobDescriptor{Name: "service", Path: "team/service"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Want an *APIError but got %v\n", err)
+	}
+	if apiErr.JobName != "team/service" {
+		t.Fatalf("Want job name team/service but got %s\n", apiErr.JobName)
+	}
+}
+
 func TestJobSummariesFromFilesystem(t *testing.T) {
 	root, err := extractTestConfigs()
 	if err != nil {
@@ -344,7 +368,7 @@ func TestJobSummariesFromFilesystem(t *testing.T) {
 		os.RemoveAll(root)
 	}()
 
-	jenkinsClient := Client{baseURL: nil, userName: "u", password: "p"}
+	jenkinsClient := NewClient(nil, "u", "p").(Client)
 
 	summaries, err := jenkinsClient.GetJobSummariesFromFilesystem(root)
 	if len(summaries) != 2 {
@@ -383,10 +407,249 @@ func TestJobSummariesFromFilesystemNoSuchRoot(t *testing.T) {
 		os.RemoveAll(root)
 	}()
 
-	jenkinsClient := Client{baseURL: nil, userName: "u", password: "p"}
+	jenkinsClient := NewClient(nil, "u", "p").(Client)
 
 	_, err = jenkinsClient.GetJobSummariesFromFilesystem(root + "/nosuchdirectory")
 	if err == nil {
 		t.Fatalf("Want an error when getting summaries from a non-existent directory\n")
 	}
 }
+
+func TestHttpJobSummariesConcurrent(t *testing.T) {
+	var inFlight, peak int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/json/jobs" {
+			var jobs []string
+			for i := 0; i < 12; i++ {
+				jobs = append(jobs, fmt.Sprintf(`{"name":"job-%02d"}`, i))
+			}
+			fmt.Fprintf(w, `{"jobs":[%s]}`, strings.Join(jobs, ","))
+			return
+		}
+
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if r.URL.Path == "/job/job-05/config.xml" {
+			fmt.Fprintln(w, "<foo/>")
+			return
+		}
+		fmt.Fprintln(w, freestyle1)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithSummaryWorkers(3), WithMaxInFlight(0))
+	summaries, err := jenkinsClient.GetJobSummaries()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if len(summaries) != 11 {
+		t.Fatalf("Want 11 summaries but got %d\n", len(summaries))
+	}
+	for i := 1; i < len(summaries); i++ {
+		if summaries[i-1].JobDescriptor.Name >= summaries[i].JobDescriptor.Name {
+			t.Fatalf("Want summaries ordered by job name but %s precedes %s\n", summaries[i-1].JobDescriptor.Name, summaries[i].JobDescriptor.Name)
+		}
+	}
+	if peak > 3 {
+		t.Fatalf("Want at most 3 concurrent config fetches but saw %d\n", peak)
+	}
+	if peak < 2 {
+		t.Fatalf("Want config fetches to run concurrently but saw %d at once\n", peak)
+	}
+}
+
+func TestHttpJobSummariesCancelled(t *testing.T) {
+	var fetches int32
+	ctx, cancel := context.WithCancel(context.Background())
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/json/jobs" {
+			fmt.Fprintln(w, `{"jobs":[{"name":"a"},{"name":"b"},{"name":"c"},{"name":"d"},{"name":"e"},{"name":"f"}]}`)
+			return
+		}
+		atomic.AddInt32(&fetches, 1)
+		cancel()
+		fmt.Fprintln(w, freestyle1)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithSummaryWorkers(1))
+	if _, err := jenkinsClient.GetJobSummariesContext(ctx); err != context.Canceled {
+		t.Fatalf("Want context.Canceled but got %v\n", err)
+	}
+	if fetches > 1 {
+		t.Fatalf("Want fetching to stop after cancellation but saw %d fetches\n", fetches)
+	}
+}
+
+func TestHttpJobSummaryReport(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/json/jobs":
+			fmt.Fprintln(w, `{"jobs":[{"name":"good"},{"name":"gone"},{"name":"garbled"},{"name":"odd"}]}`)
+		case "/job/good/config.xml":
+			fmt.Fprintln(w, freestyle1)
+		case "/job/gone/config.xml":
+			w.WriteHeader(http.StatusNotFound)
+		case "/job/garbled/config.xml":
+			fmt.Fprintln(w, "<project><scm>")
+		case "/job/odd/config.xml":
+			fmt.Fprintln(w, "<foo/>")
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	report, err := jenkinsClient.GetJobSummaryReport(context.Background(), SummaryOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if len(report.Summaries) != 1 || report.Summaries[0].JobDescriptor.Name != "good" {
+		t.Fatalf("Want only the summary of job good but got %v\n", report.Summaries)
+	}
+
+	want := []JobSummaryError{
+		{JobName: "garbled", Phase: PhaseParse},
+		{JobName: "gone", Phase: PhaseFetch},
+		{JobName: "odd", Phase: PhaseUnknownType},
+	}
+	if len(report.Errors) != len(want) {
+		t.Fatalf("Want %d errors but got %d\n", len(want), len(report.Errors))
+	}
+	for i, w := range want {
+		if report.Errors[i].JobName != w.JobName || report.Errors[i].Phase != w.Phase {
+			t.Fatalf("Want error for job %s in phase %s but got job %s in phase %s\n", w.JobName, w.Phase, report.Errors[i].JobName, report.Errors[i].Phase)
+		}
+	}
+	if !IsNotFound(report.Errors[1]) {
+		t.Fatalf("Want the fetch error to be a not-found error but got %v\n", report.Errors[1].Err)
+	}
+}
+
+func TestHttpJobSummaryReportFailFast(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/json/jobs" {
+			fmt.Fprintln(w, `{"jobs":[{"name":"a"},{"name":"b"},{"name":"c"}]}`)
+			return
+		}
+		fmt.Fprintln(w, "<foo/>")
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithSummaryWorkers(1))
+	report, err := jenkinsClient.GetJobSummaryReport(context.Background(), SummaryOptions{FailFast: true})
+	var summaryErr *JobSummaryError
+	if !errors.As(err, &summaryErr) {
+		t.Fatalf("Want a *JobSummaryError but got %v\n", err)
+	}
+	if summaryErr.JobName != "a" || summaryErr.Phase != PhaseUnknownType {
+		t.Fatalf("Want job a to fail with an unknown type but got %v\n", summaryErr)
+	}
+	if len(report.Errors) != 1 {
+		t.Fatalf("Want 1 error in the report but got %d\n", len(report.Errors))
+	}
+}
+
+func TestJobSummaryReportFromFilesystem(t *testing.T) {
+	root, err := extractTestConfigs()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer func() {
+		os.RemoveAll(root)
+	}()
+	if err := os.MkdirAll(root+"/broken", 0755); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := ioutil.WriteFile(root+"/broken/config.xml", []byte("<foo/>"), 0644); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	jenkinsClient := NewClient(nil, "u", "p").(Client)
+	report, err := jenkinsClient.GetJobSummaryReportFromFilesystem(root, SummaryOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if len(report.Summaries) != 2 {
+		t.Fatalf("Want 2 summaries but got %d\n", len(report.Summaries))
+	}
+	if len(report.Errors) != 1 || report.Errors[0].JobName != "broken" || report.Errors[0].Phase != PhaseUnknownType {
+		t.Fatalf("Want job broken reported with an unknown type but got %v\n", report.Errors)
+	}
+
+	if _, err := jenkinsClient.GetJobSummaryReportFromFilesystem(root, SummaryOptions{FailFast: true}); err == nil {
+		t.Fatalf("Want an error with FailFast but got none\n")
+	}
+}
+
+func TestHttpJobSummaryDisabled(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, strings.Replace(freestyle1, "<disabled>false</disabled>", "<disabled>true</disabled>", 1))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p").(Client)
+	summary, err := jenkinsClient.getJobSummary(context.Background(), JobDescriptor{Name: "thejob"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if !summary.Disabled {
+		t.Fatalf("Want a disabled job\n")
+	}
+}
+
+func TestJobSummariesFromFilesystemDisabled(t *testing.T) {
+	root, err := extractTestConfigs()
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer func() {
+		os.RemoveAll(root)
+	}()
+	if err := os.MkdirAll(root+"/frozen", 0755); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if err := ioutil.WriteFile(root+"/frozen/config.xml", []byte("<project><disabled>true</disabled></project>"), 0644); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	jenkinsClient := NewClient(nil, "u", "p").(Client)
+	summaries, err := jenkinsClient.GetJobSummariesFromFilesystem(root)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	for _, v := range summaries {
+		if v.Disabled != (v.JobDescriptor.Name == "frozen") {
+			t.Fatalf("Want only job frozen disabled but job %s has Disabled %v\n", v.JobDescriptor.Name, v.Disabled)
+		}
+	}
+}
+
+func TestHttpJobSummaryXML11(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		document := strings.Replace(strings.TrimSpace(freestyle1), "version='1.0'", "version='1.1'", 1)
+		fmt.Fprintln(w, strings.Replace(document, "<disabled>false</disabled>", "<disabled>true</disabled>", 1))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p").(Client)
+	summary, err := jenkinsClient.getJobSummary(context.Background(), JobDescriptor{Name: "thejob"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if summary.JobType != Freestyle || !summary.Disabled {
+		t.Fatalf("Want a disabled Freestyle job but got %+v\n", summary)
+	}
+}
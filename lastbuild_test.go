@@ -13,6 +13,9 @@ func TestGetLastBuild(t *testing.T) {
 		if url.Path != "/job/thejob/lastBuild/api/json" {
 			t.Fatalf("Want /job/thejob/lastBuild/api/json but got %s\n", url.Path)
 		}
+		if tree := url.Query().Get("tree"); tree != "timestamp,result,url" {
+			t.Fatalf("Want tree timestamp,result,url but got %s\n", tree)
+		}
 		if r.Header.Get("Accept") != "application/json" {
 			t.Fatalf("Want application/json but got %s\n", r.Header.Get("Accept"))
 		}
@@ -0,0 +1,104 @@
+package jenkins
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is the number of requests per second a client sends at most unless configured otherwise.
+	DefaultRateLimit = 20
+
+	// DefaultMaxInFlight is the number of requests a client has outstanding at most unless configured otherwise.
+	DefaultMaxInFlight = 8
+)
+
+// limiter paces the requests of a client and of all its copies.  It is a token bucket refilled at rate tokens
+// per second holding at most burst tokens, combined with a semaphore capping the requests in flight.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	slots  chan struct{}
+}
+
+// WithRateLimit lets the client send at most requestsPerSecond requests per second, with bursts of up to burst
+// requests.  A rate of zero or less removes the limit.  The default is DefaultRateLimit with an equal burst.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(o *options) {
+		o.rateLimit = requestsPerSecond
+		o.burst = burst
+	}
+}
+
+// WithMaxInFlight lets the client have at most n requests outstanding at once.  Zero or less removes the cap.
+// The default is DefaultMaxInFlight.
+func WithMaxInFlight(n int) Option {
+	return func(o *options) {
+		o.maxInFlight = n
+	}
+}
+
+func newLimiter(rate float64, burst, maxInFlight int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// acquire blocks until a request may be sent, or ctx is done.  The returned function must be called once the
+// request has completed.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if err := l.wait(ctx); err != nil {
+		return nil, err
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait takes a token from the bucket, waiting for one to become available if the bucket is empty.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if d == 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, d); err != nil {
+		// Hand back the token reserved for a request that will not be sent.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRateLimit(20, 1))
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := jenkinsClient.GetJobs(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("Want 5 requests at 20/s to take at least 200ms but took %v\n", elapsed)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var inFlight, peak int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRateLimit(0, 0), WithMaxInFlight(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := jenkinsClient.GetJobs(); err != nil {
+				t.Errorf("Unexpected error: %v\n", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if peak > 2 {
+		t.Fatalf("Want at most 2 requests in flight but saw %d\n", peak)
+	}
+}
+
+func TestLimiterWaitHonorsContext(t *testing.T) {
+	l := newLimiter(1, 1, 0)
+	if _, err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Fatalf("Want the context error while waiting for a token but got none\n")
+	}
+}
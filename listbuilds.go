@@ -0,0 +1,144 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DefaultBuildPageSize is the number of builds ListBuilds fetches per request.
+const DefaultBuildPageSize = 100
+
+// buildListFields are the fields of the builds ListBuilds fetches.  Causes, parameters, artifacts and change sets
+// are left out to keep pages small; use GetBuild for them.
+const buildListFields = "number,url,displayName,description,queueId,building,result,timestamp,duration,estimatedDuration,builtOn"
+
+// BuildIterator walks the builds of a job, newest first, fetching them a page at a time as they are needed.
+//
+//	it := client.ListBuilds("service", jenkins.ListBuildsOptions{Result: "FAILURE"})
+//	for it.Next() {
+//		build := it.Build()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type BuildIterator struct {
+	client  Client
+	ctx     context.Context
+	jobName string
+	opts    ListBuildsOptions
+
+	page  []Build
+	from  int // the index of the first build of the next page
+	last  bool
+	build Build
+	err   error
+}
+
+// ListBuilds is ListBuildsContext with a background context.
+func (client Client) ListBuilds(jobName string, opts ListBuildsOptions) *BuildIterator {
+	return client.ListBuildsContext(context.Background(), jobName, opts)
+}
+
+// ListBuildsContext returns an iterator over the builds of the named job selected by opts, newest first.  Pages
+// are requested with the allBuilds{from,to} range of the Jenkins API, so jobs with long histories are listed
+// without fetching every build at once.  The builds carry no causes, parameters, artifacts or change sets.
+func (client Client) ListBuildsContext(ctx context.Context, jobName string, opts ListBuildsOptions) *BuildIterator {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultBuildPageSize
+	}
+	it := &BuildIterator{client: client, ctx: withJob(ctx, jobName), jobName: jobName, opts: opts}
+	it.err = checkJobPath(jobName)
+	return it
+}
+
+// Next advances to the next selected build, and reports whether there is one.  It returns false at the end of the
+// builds or on an error; see Err.
+func (it *BuildIterator) Next() bool {
+	for it.err == nil {
+		if len(it.page) == 0 {
+			if it.last {
+				return false
+			}
+			if it.err = it.fetch(); it.err != nil {
+				return false
+			}
+			continue
+		}
+		build := it.page[0]
+		it.page = it.page[1:]
+		if !it.opts.Since.IsZero() && build.Timestamp.Before(it.opts.Since) {
+			// Builds come newest first, so all the remaining builds are older still.
+			it.page, it.last = nil, true
+			return false
+		}
+		if it.selects(build) {
+			it.build = build
+			return true
+		}
+	}
+	return false
+}
+
+// Build returns the build Next advanced to.
+func (it *BuildIterator) Build() Build {
+	return it.build
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *BuildIterator) Err() error {
+	return it.err
+}
+
+// selects reports whether build is selected by the result and Until of the iterator's options.
+func (it *BuildIterator) selects(build Build) bool {
+	if it.opts.Result != "" && (build.Building || build.Result != it.opts.Result) {
+		return false
+	}
+	return it.opts.Until.IsZero() || build.Timestamp.Before(it.opts.Until)
+}
+
+// fetch requests the next page of builds.
+func (it *BuildIterator) fetch() error {
+	to := it.from + it.opts.PageSize
+	tree := fmt.Sprintf("allBuilds[%s]{%d,%d}", buildListFields, it.from, to)
+	var data []byte
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", it.client.resourceURL(it.jobName, "api/json", url.Values{"tree": {tree}}), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		var response *http.Response
+		response, data, err = it.client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, it.jobName)
+		}
+		return nil
+	}
+	if err := it.client.try(it.ctx, work); err != nil {
+		return err
+	}
+
+	var builds struct {
+		AllBuilds []buildJSON `json:"allBuilds"`
+	}
+	if err := json.Unmarshal(data, &builds); err != nil {
+		return err
+	}
+	it.page = make([]Build, 0, len(builds.AllBuilds))
+	for _, b := range builds.AllBuilds {
+		it.page = append(it.page, b.build())
+	}
+	it.from = to
+	it.last = len(builds.AllBuilds) < it.opts.PageSize
+	return nil
+}
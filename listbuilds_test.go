@@ -0,0 +1,118 @@
+package jenkins
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var buildRange = regexp.MustCompile(`^allBuilds\[.*\]\{(\d+),(\d+)\}$`)
+
+var historyStart = time.Date(2016, 2, 1, 0, 0, 0, 0, time.UTC)
+
+// buildHistoryServer serves the builds 250 down to 1 of job service, started an hour apart, with every
+// tenth build failed.  It counts the pages requested.
+func buildHistoryServer(t *testing.T, pages *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/service/api/json" {
+			t.Fatalf("wanted URL path /job/service/api/json but found %s\n", r.URL.Path)
+		}
+		match := buildRange.FindStringSubmatch(r.URL.Query().Get("tree"))
+		if match == nil {
+			t.Fatalf("want an allBuilds range but got %s\n", r.URL.Query().Get("tree"))
+		}
+		*pages++
+		from, _ := strconv.Atoi(match[1])
+		to, _ := strconv.Atoi(match[2])
+		builds := make([]map[string]interface{}, 0)
+		for i := from; i < to && i < 250; i++ {
+			number := 250 - i
+			result := "SUCCESS"
+			if number%10 == 0 {
+				result = "FAILURE"
+			}
+			builds = append(builds, map[string]interface{}{
+				"number":    number,
+				"result":    result,
+				"timestamp": historyStart.Add(time.Duration(number) * time.Hour).UnixMilli(),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"allBuilds": builds})
+	}))
+}
+
+func TestListBuilds(t *testing.T) {
+	pages := 0
+	testServer := buildHistoryServer(t, &pages)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	it := jenkinsClient.ListBuilds("service", ListBuildsOptions{})
+	want := 250
+	for it.Next() {
+		if it.Build().Number != want {
+			t.Fatalf("want build %d but got %d\n", want, it.Build().Number)
+		}
+		want--
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if want != 0 {
+		t.Fatalf("want all builds but stopped before %d\n", want)
+	}
+	if pages != 3 {
+		t.Fatalf("want 3 pages but got %d\n", pages)
+	}
+}
+
+func TestListBuildsFiltered(t *testing.T) {
+	pages := 0
+	testServer := buildHistoryServer(t, &pages)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	it := jenkinsClient.ListBuilds("service", ListBuildsOptions{
+		PageSize: 20,
+		Result:   "FAILURE",
+		Since:    historyStart.Add(200 * time.Hour),
+		Until:    historyStart.Add(240 * time.Hour),
+	})
+	var numbers []int
+	for it.Next() {
+		numbers = append(numbers, it.Build().Number)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if len(numbers) != 4 || numbers[0] != 230 || numbers[3] != 200 {
+		t.Fatalf("want failed builds 230 down to 200 but got %v\n", numbers)
+	}
+	if pages != 3 {
+		t.Fatalf("want 3 pages, stopping at build 200, but got %d\n", pages)
+	}
+}
+
+func TestListBuildsNotFound(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	it := jenkinsClient.ListBuilds("service", ListBuildsOptions{})
+	if it.Next() {
+		t.Fatalf("want no builds\n")
+	}
+	if !IsNotFound(it.Err()) {
+		t.Fatalf("want a not-found error but got %v\n", it.Err())
+	}
+}
@@ -0,0 +1,122 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	jobKey     struct{}
+	attemptKey struct{}
+)
+
+// discardLogger is the logger of clients not given WithLogger.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// redactedQueryParameters are query parameters whose values are secrets, such as remote build trigger tokens.
+var redactedQueryParameters = []string{"token", "password", "secret"}
+
+// WithLogger makes the client log to logger.  Every request is logged at debug level with its method, URL,
+// status, attempt, duration and job.  Retries, failed requests and 5xx responses are logged at warn level.
+// Credentials are never logged.  Without this option the client logs nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// log returns the logger of the client.
+func (client Client) log() *slog.Logger {
+	if client.logger == nil {
+		return discardLogger
+	}
+	return client.logger
+}
+
+// withJob records in ctx the name of the job requests made with ctx are about.
+func withJob(ctx context.Context, jobName string) context.Context {
+	return context.WithValue(ctx, jobKey{}, jobName)
+}
+
+// jobFrom returns the job name recorded by withJob, if any.
+func jobFrom(ctx context.Context) string {
+	jobName, _ := ctx.Value(jobKey{}).(string)
+	return jobName
+}
+
+// withAttempt records in ctx which attempt, counting from 1, requests made with ctx are.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFrom returns the attempt recorded by withAttempt.  Requests that are never retried are attempt 1.
+func attemptFrom(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+// logRequest logs the outcome of req, which received response with a body of size bytes, or failed with err.
+func (client Client) logRequest(req *http.Request, response *http.Response, size int, duration time.Duration, err error) {
+	logger := client.log()
+	ctx := req.Context()
+	level := slog.LevelDebug
+	if err != nil || response.StatusCode >= 500 {
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Int("attempt", attemptFrom(ctx)),
+		slog.Duration("duration", duration),
+	}
+	if jobName := jobFrom(ctx); jobName != "" {
+		attrs = append(attrs, slog.String("job", jobName))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redactError(err)))
+		logger.LogAttrs(ctx, level, "Jenkins request failed", attrs...)
+		return
+	}
+	attrs = append(attrs, slog.Int("status", response.StatusCode), slog.Int("bytes", size))
+	logger.LogAttrs(ctx, level, "Jenkins request", attrs...)
+}
+
+// redactURL renders u without its password and without the values of secret query parameters.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User(redacted.User.Username())
+	}
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for key := range query {
+			for _, secret := range redactedQueryParameters {
+				if strings.EqualFold(key, secret) {
+					query.Set(key, "xxxxx")
+				}
+			}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// redactError renders err without the URL an *url.Error would include, since the URL may hold secrets.
+func redactError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op + ": " + urlErr.Err.Error()
+	}
+	return err.Error()
+}
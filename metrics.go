@@ -0,0 +1,205 @@
+package jenkins
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the buckets of the request duration histogram.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type (
+	// Metrics is an Instrumentation that keeps Prometheus-style counters and histograms of a client's requests in
+	// process.  Expose them with WritePrometheus, or mount the Metrics as an http.Handler.  The metrics are:
+	//
+	//	jenkins_client_requests_total{method,endpoint,code}       counter; code is "error" for transport errors
+	//	jenkins_client_request_duration_seconds{method,endpoint}  histogram
+	//	jenkins_client_response_bytes_total{method,endpoint}      counter
+	//	jenkins_client_requests_in_flight                         gauge
+	//	jenkins_client_retries_total{reason}                      counter; reason is a status code or "network"
+	Metrics struct {
+		mu        sync.Mutex
+		buckets   []float64
+		inFlight  int64
+		requests  map[requestKey]uint64
+		bytes     map[endpointKey]uint64
+		durations map[endpointKey]*histogram
+		retries   map[string]uint64
+	}
+
+	endpointKey struct {
+		method   string
+		endpoint string
+	}
+
+	requestKey struct {
+		endpointKey
+		code string
+	}
+
+	histogram struct {
+		counts []uint64 // per bucket, not cumulative
+		count  uint64
+		sum    float64
+	}
+)
+
+// NewMetrics returns Metrics with the DefaultDurationBuckets.  Pass it to WithInstrumentation.
+func NewMetrics() *Metrics {
+	return NewMetricsWithBuckets(DefaultDurationBuckets)
+}
+
+// NewMetricsWithBuckets returns Metrics whose duration histogram has buckets with the given upper bounds, in
+// seconds.
+func NewMetricsWithBuckets(buckets []float64) *Metrics {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Metrics{
+		buckets:   sorted,
+		requests:  make(map[requestKey]uint64),
+		bytes:     make(map[endpointKey]uint64),
+		durations: make(map[endpointKey]*histogram),
+		retries:   make(map[string]uint64),
+	}
+}
+
+func (m *Metrics) RequestStarted(info RequestInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight++
+}
+
+func (m *Metrics) RequestFinished(info RequestInfo, result RequestResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+
+	key := endpointKey{method: info.Method, endpoint: info.Endpoint}
+	code := "error"
+	if result.Err == nil {
+		code = strconv.Itoa(result.StatusCode)
+	}
+	m.requests[requestKey{endpointKey: key, code: code}]++
+	m.bytes[key] += uint64(result.BytesRead)
+
+	h, ok := m.durations[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[key] = h
+	}
+	seconds := result.Duration.Seconds()
+	h.count++
+	h.sum += seconds
+	if i := sort.SearchFloat64s(m.buckets, seconds); i < len(m.buckets) {
+		h.counts[i]++
+	}
+}
+
+func (m *Metrics) RetryScheduled(info RetryInfo) {
+	reason := "network"
+	var apiErr *APIError
+	if errors.As(info.Err, &apiErr) {
+		reason = strconv.Itoa(apiErr.StatusCode)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[reason]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WritePrometheus(w)
+}
+
+// WritePrometheus writes the metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := bufio.NewWriter(w)
+
+	writeHeader(b, "jenkins_client_requests_total", "counter", "Requests made to Jenkins.")
+	requestKeys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		requestKeys = append(requestKeys, k)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		if requestKeys[i].endpointKey != requestKeys[j].endpointKey {
+			return requestKeys[i].endpointKey.less(requestKeys[j].endpointKey)
+		}
+		return requestKeys[i].code < requestKeys[j].code
+	})
+	for _, k := range requestKeys {
+		fmt.Fprintf(b, "jenkins_client_requests_total{method=%s,endpoint=%s,code=%s} %d\n", quote(k.method), quote(k.endpoint), quote(k.code), m.requests[k])
+	}
+
+	writeHeader(b, "jenkins_client_request_duration_seconds", "histogram", "Duration of requests made to Jenkins, including reading the response body.")
+	for _, k := range m.endpointKeys() {
+		h := m.durations[k]
+		labels := fmt.Sprintf("method=%s,endpoint=%s", quote(k.method), quote(k.endpoint))
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "jenkins_client_request_duration_seconds_bucket{%s,le=%s} %d\n", labels, quote(strconv.FormatFloat(bound, 'g', -1, 64)), cumulative)
+		}
+		fmt.Fprintf(b, "jenkins_client_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(b, "jenkins_client_request_duration_seconds_sum{%s} %g\n", labels, h.sum)
+		fmt.Fprintf(b, "jenkins_client_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	writeHeader(b, "jenkins_client_response_bytes_total", "counter", "Response body bytes read from Jenkins.")
+	for _, k := range m.endpointKeys() {
+		fmt.Fprintf(b, "jenkins_client_response_bytes_total{method=%s,endpoint=%s} %d\n", quote(k.method), quote(k.endpoint), m.bytes[k])
+	}
+
+	writeHeader(b, "jenkins_client_requests_in_flight", "gauge", "Requests to Jenkins awaiting their response.")
+	fmt.Fprintf(b, "jenkins_client_requests_in_flight %d\n", m.inFlight)
+
+	writeHeader(b, "jenkins_client_retries_total", "counter", "Requests to Jenkins retried after a failure.")
+	reasons := make([]string, 0, len(m.retries))
+	for reason := range m.retries {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(b, "jenkins_client_retries_total{reason=%s} %d\n", quote(reason), m.retries[reason])
+	}
+
+	return b.Flush()
+}
+
+func (k endpointKey) less(other endpointKey) bool {
+	if k.endpoint != other.endpoint {
+		return k.endpoint < other.endpoint
+	}
+	return k.method < other.method
+}
+
+// endpointKeys returns the method and endpoint pairs seen so far, in order.
+func (m *Metrics) endpointKeys() []endpointKey {
+	keys := make([]endpointKey, 0, len(m.durations))
+	for k := range m.durations {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	return keys
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quote renders a label value, escaping as the exposition format requires.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
@@ -0,0 +1,119 @@
+package jenkins
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingInstrumentation struct {
+	started  []RequestInfo
+	finished []RequestResult
+	retries  []RetryInfo
+}
+
+func (r *recordingInstrumentation) RequestStarted(info RequestInfo) {
+	r.started = append(r.started, info)
+}
+
+func (r *recordingInstrumentation) RequestFinished(info RequestInfo, result RequestResult) {
+	r.finished = append(r.finished, result)
+}
+
+func (r *recordingInstrumentation) RetryScheduled(info RetryInfo) {
+	r.retries = append(r.retries, info)
+}
+
+func flakyConfigServer() *httptest.Server {
+	var calls int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(jobConfig))
+	}))
+}
+
+func TestWithInstrumentation(t *testing.T) {
+	testServer := flakyConfigServer()
+	defer testServer.Close()
+
+	recorder := &recordingInstrumentation{}
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithInstrumentation(recorder), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	if _, err := jenkinsClient.GetJobConfig("thejob"); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	if len(recorder.started) != 2 || len(recorder.finished) != 2 || len(recorder.retries) != 1 {
+		t.Fatalf("Want 2 requests and 1 retry but got %d started, %d finished and %d retries\n", len(recorder.started), len(recorder.finished), len(recorder.retries))
+	}
+	second := recorder.started[1]
+	if second.Endpoint != "/job/:name/config.xml" || second.JobName != "thejob" || second.Attempt != 2 || second.Method != "GET" {
+		t.Fatalf("Want the second attempt at GET /job/:name/config.xml for thejob but got %+v\n", second)
+	}
+	if recorder.finished[0].StatusCode != 500 || recorder.finished[1].StatusCode != 200 {
+		t.Fatalf("Want statuses 500 and 200 but got %d and %d\n", recorder.finished[0].StatusCode, recorder.finished[1].StatusCode)
+	}
+	if recorder.finished[1].BytesRead != len(jobConfig) {
+		t.Fatalf("Want %d bytes read but got %d\n", len(jobConfig), recorder.finished[1].BytesRead)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	testServer := flakyConfigServer()
+	defer testServer.Close()
+
+	metrics := NewMetrics()
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithInstrumentation(metrics), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	if _, err := jenkinsClient.GetJobConfig("thejob"); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.WritePrometheus(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	for _, want := range []string{
+		`jenkins_client_requests_total{method="GET",endpoint="/job/:name/config.xml",code="200"} 1`,
+		`jenkins_client_requests_total{method="GET",endpoint="/job/:name/config.xml",code="500"} 1`,
+		`jenkins_client_request_duration_seconds_bucket{method="GET",endpoint="/job/:name/config.xml",le="+Inf"} 2`,
+		`jenkins_client_request_duration_seconds_count{method="GET",endpoint="/job/:name/config.xml"} 2`,
+		`jenkins_client_requests_in_flight 0`,
+		`jenkins_client_retries_total{reason="500"} 1`,
+		`# TYPE jenkins_client_request_duration_seconds histogram`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("Want %s in the metrics but got:\n%s\n", want, buf.String())
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") || recorder.Body.Len() == 0 {
+		t.Fatalf("Want the metrics served as text but got %q\n", recorder.Header().Get("Content-Type"))
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	base, _ := url.Parse("https://ci.example.com/jenkins/")
+	jenkinsClient := NewClient(base, "u", "p").(Client)
+	for path, want := range map[string]string{
+		"/jenkins/job/thejob/config.xml":            "/job/:name/config.xml",
+		"/jenkins/job/team/job/service/42/api/json": "/job/:name/job/:name/:number/api/json",
+		"/jenkins/queue/item/1234/api/json":         "/queue/item/:number/api/json",
+		"/jenkins/api/json/jobs":                    "/api/json/jobs",
+	} {
+		req := httptest.NewRequest("GET", "https://ci.example.com"+path, nil)
+		if got := jenkinsClient.endpoint(req); got != want {
+			t.Fatalf("Want endpoint %s for %s but got %s\n", want, path, got)
+		}
+	}
+}
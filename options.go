@@ -0,0 +1,137 @@
+package jenkins
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type (
+	// Option configures a Client built by NewClient.
+	Option func(*options)
+
+	// options collects the settings of the Option values passed to NewClient.
+	options struct {
+		authenticator Authenticator
+		transport     http.RoundTripper
+		timeout       time.Duration
+		tlsConfig     *tls.Config
+		proxy         func(*http.Request) (*url.URL, error)
+		userAgent     string
+		retryPolicy   *RetryPolicy
+		rateLimit     float64
+		burst         int
+		maxInFlight   int
+
+		summaryWorkers int
+		logger         *slog.Logger
+
+		instrumentation Instrumentation
+	}
+)
+
+// WithTransport makes the client send its requests through rt instead of a copy of http.DefaultTransport.  The
+// TLS and proxy options have no effect on a transport supplied this way.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = rt
+	}
+}
+
+// WithTimeout bounds each individual request, including reading its response body.  Retried requests get a
+// fresh timeout per attempt.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to reach Jenkins.  The config is cloned, so later changes to
+// cfg do not affect the client.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = cfg.Clone()
+	}
+}
+
+// WithRootCAs trusts the certificate authorities in pool, for instance a private CA, when verifying the
+// Jenkins server certificate.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(o *options) {
+		o.tls().RootCAs = pool
+	}
+}
+
+// WithClientCertificate presents cert to Jenkins for mutual TLS authentication.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(o *options) {
+		cfg := o.tls()
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+}
+
+// WithProxy routes requests through the proxy returned by proxy, which has the semantics of
+// http.Transport.Proxy.  Use http.ProxyURL for a fixed proxy.  Without this option the proxy settings in the
+// environment are honored.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(o *options) {
+		o.proxy = proxy
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// DefaultSummaryWorkers is the number of job configs GetJobSummaries fetches at once unless configured otherwise.
+const DefaultSummaryWorkers = 4
+
+// WithSummaryWorkers lets GetJobSummaries fetch up to n job configs at once.  The requests still count against
+// the in-flight cap set by WithMaxInFlight.
+func WithSummaryWorkers(n int) Option {
+	return func(o *options) {
+		o.summaryWorkers = n
+	}
+}
+
+// tls returns the TLS configuration being built, creating it on first use.
+func (o *options) tls() *tls.Config {
+	if o.tlsConfig == nil {
+		o.tlsConfig = &tls.Config{}
+	}
+	return o.tlsConfig
+}
+
+// httpClient builds the http.Client described by the options.
+func (o *options) httpClient() *http.Client {
+	transport := o.transport
+	if transport == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		if o.tlsConfig != nil {
+			t.TLSClientConfig = o.tlsConfig
+		}
+		if o.proxy != nil {
+			t.Proxy = o.proxy
+		}
+		transport = t
+	}
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       o.timeout,
+		CheckRedirect: doNotFollowRedirects,
+	}
+}
+
+// doNotFollowRedirects hands redirect responses back to the caller as-is.  See consumeResponse.
+func doNotFollowRedirects(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// defaultHTTPClient serves clients that were not built by NewClient.
+var defaultHTTPClient = &http.Client{CheckRedirect: doNotFollowRedirects}
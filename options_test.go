@@ -0,0 +1,111 @@
+package jenkins
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer testServer.Close()
+
+	transport := &countingTransport{}
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithTransport(transport))
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if transport.requests != 1 {
+		t.Fatalf("Want 1 request through the custom transport but got %d\n", transport.requests)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "job-sync/1.0" {
+			t.Fatalf("Want User-Agent job-sync/1.0 but got %s\n", r.Header.Get("User-Agent"))
+		}
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithUserAgent("job-sync/1.0"))
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithTimeout(20*time.Millisecond))
+	if _, err := jenkinsClient.GetLastBuild("thejob"); err == nil {
+		t.Fatalf("Want a timeout error but got none\n")
+	}
+}
+
+func TestWithRootCAsAndClientCertificate(t *testing.T) {
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) != 1 {
+			t.Fatalf("Want 1 client certificate but got %d\n", len(r.TLS.PeerCertificates))
+		}
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	testServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(testServer.Certificate())
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRootCAs(pool), WithClientCertificate(testServer.TLS.Certificates[0]))
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	untrusting := NewClient(url, "u", "p", WithClientCertificate(testServer.TLS.Certificates[0]))
+	if _, err := untrusting.GetJobs(); err == nil {
+		t.Fatalf("Want a certificate verification error without the private CA but got none\n")
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "jenkins.example.com" {
+			t.Fatalf("Want proxied request for jenkins.example.com but got %s\n", r.URL.Host)
+		}
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	jenkinsURL, _ := url.Parse("http://jenkins.example.com")
+	jenkinsClient := NewClient(jenkinsURL, "u", "p", WithProxy(http.ProxyURL(proxyURL)))
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+}
@@ -0,0 +1,49 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+)
+
+// PermalinkNotFoundError is returned when a job exists but has no build for a permalink yet, such as a job that
+// never failed asked for its last failed build.  It matches ErrNotFound.
+type PermalinkNotFoundError struct {
+	JobName   string
+	Permalink Permalink
+}
+
+func (e *PermalinkNotFoundError) Error() string {
+	return fmt.Sprintf("jenkins: job %s has no %s", e.JobName, e.Permalink)
+}
+
+func (e *PermalinkNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// GetBuildByPermalink is GetBuildByPermalinkContext with a background context.
+func (client Client) GetBuildByPermalink(jobName string, permalink Permalink) (Build, error) {
+	return client.GetBuildByPermalinkContext(context.Background(), jobName, permalink)
+}
+
+// GetBuildByPermalinkContext retrieves the build the permalink of the named job points to.  If the job exists but
+// the permalink does not point to a build, the error is a *PermalinkNotFoundError; if the job does not exist, it
+// is the *APIError for the job.  Both match ErrNotFound.
+func (client Client) GetBuildByPermalinkContext(ctx context.Context, jobName string, permalink Permalink) (Build, error) {
+	return client.getBuildByPermalink(ctx, jobName, permalink, buildFields)
+}
+
+// getBuildByPermalink is GetBuildByPermalinkContext fetching only the fields named by tree.
+func (client Client) getBuildByPermalink(ctx context.Context, jobName string, permalink Permalink, tree string) (Build, error) {
+	if permalink == "" {
+		return Build{}, fmt.Errorf("jenkins: empty permalink for job %s", jobName)
+	}
+	build, err := client.getBuild(ctx, jobName, string(permalink), tree)
+	if err == nil || !IsNotFound(err) {
+		return build, err
+	}
+	exists, existsErr := client.JobExistsContext(ctx, jobName)
+	if existsErr != nil || !exists {
+		return Build{}, err
+	}
+	return Build{}, &PermalinkNotFoundError{JobName: jobName, Permalink: permalink}
+}
@@ -0,0 +1,47 @@
+package jenkins
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetBuildByPermalink(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/job/team/job/service/lastSuccessfulBuild/api/json":
+			fmt.Fprintln(w, `{"number":16,"result":"SUCCESS"}`)
+		case "/job/team/job/service/lastFailedBuild/api/json":
+			w.WriteHeader(http.StatusNotFound)
+		case "/job/team/job/service/api/json":
+			fmt.Fprintln(w, `{"name":"service"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	build, err := jenkinsClient.GetBuildByPermalink("team/service", PermalinkLastSuccessfulBuild)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if build.Number != 16 || build.Result != "SUCCESS" {
+		t.Fatalf("unexpected build %+v\n", build)
+	}
+
+	_, err = jenkinsClient.GetBuildByPermalink("team/service", PermalinkLastFailedBuild)
+	var permalinkErr *PermalinkNotFoundError
+	if !errors.As(err, &permalinkErr) || permalinkErr.Permalink != PermalinkLastFailedBuild || !IsNotFound(err) {
+		t.Fatalf("want a *PermalinkNotFoundError for lastFailedBuild but got %v\n", err)
+	}
+
+	_, err = jenkinsClient.GetBuildByPermalink("team/missing", PermalinkLastBuild)
+	if errors.As(err, &permalinkErr) || !IsNotFound(err) {
+		t.Fatalf("want a not-found error for the job but got %v\n", err)
+	}
+}
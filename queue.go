@@ -0,0 +1,82 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// GetQueueItem is GetQueueItemContext with a background context.
+func (client Client) GetQueueItem(id int64) (QueueItem, error) {
+	return client.GetQueueItemContext(context.Background(), id)
+}
+
+// GetQueueItemContext retrieves the state of the queue item with the given ID.  Jenkins forgets items a few
+// minutes after they leave the queue, after which this fails with an error matching ErrNotFound.
+func (client Client) GetQueueItemContext(ctx context.Context, id int64) (QueueItem, error) {
+	resource := fmt.Sprintf("queue/item/%d/", id)
+	var data []byte
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL("", resource+"api/json", nil), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		var response *http.Response
+		response, data, err = client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, "")
+		}
+		return nil
+	}
+	if err := client.try(ctx, work); err != nil {
+		return QueueItem{}, err
+	}
+
+	var item QueueItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return QueueItem{}, err
+	}
+	item.URL = client.resourceURL("", resource, nil)
+	return item, nil
+}
+
+// WaitForBuildStart polls the queue item until it leaves the queue, and returns the number and URL of the build it
+// became.  If the item is cancelled instead, the error matches ErrQueueItemCancelled.  Waiting ends with ctx's
+// error when ctx is done.
+func (client Client) WaitForBuildStart(ctx context.Context, item QueueItem, opts ...WaitOption) (QueueExecutable, error) {
+	o := newWaitOptions(opts)
+	var executable QueueExecutable
+	err := o.poll(ctx, func() (bool, error) {
+		current, err := client.GetQueueItemContext(ctx, item.ID)
+		if err != nil {
+			return false, err
+		}
+		if current.Cancelled {
+			return false, fmt.Errorf("%w: queue item %d", ErrQueueItemCancelled, item.ID)
+		}
+		if current.Executable != nil {
+			executable = *current.Executable
+			return true, nil
+		}
+		client.log().LogAttrs(ctx, slog.LevelDebug, "waiting for queued Jenkins build",
+			slog.Int64("queue_item", item.ID),
+			slog.String("why", current.Why),
+		)
+		if o.queueProgress != nil {
+			o.queueProgress(current)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return QueueExecutable{}, err
+	}
+	return executable, nil
+}
@@ -0,0 +1,111 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var fastPolls = WithPollInterval(time.Millisecond, 2*time.Millisecond)
+
+func TestGetQueueItem(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/queue/item/42/api/json" {
+			t.Fatalf("wanted URL path /queue/item/42/api/json but found %s\n", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"id":42,"url":"queue/item/42/","why":"Waiting for next available executor","buildable":true,"inQueueSince":1431097640000}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	item, err := jenkinsClient.GetQueueItem(42)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if item.ID != 42 || !item.Buildable || item.Why != "Waiting for next available executor" || item.Executable != nil {
+		t.Fatalf("unexpected queue item %+v\n", item)
+	}
+	if item.URL != testServer.URL+"/queue/item/42/" {
+		t.Fatalf("want an absolute URL but got %s\n", item.URL)
+	}
+}
+
+func TestWaitForBuildStart(t *testing.T) {
+	polls := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			fmt.Fprintln(w, `{"id":42,"why":"In the quiet period"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"id":42,"executable":{"number":17,"url":"http://jenkins/job/service/17/"}}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	var reasons []string
+	executable, err := jenkinsClient.WaitForBuildStart(context.Background(), QueueItem{ID: 42}, fastPolls, WithQueueProgress(func(item QueueItem) {
+		reasons = append(reasons, item.Why)
+	}))
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if executable.Number != 17 || executable.URL != "http://jenkins/job/service/17/" {
+		t.Fatalf("unexpected executable %+v\n", executable)
+	}
+	if len(reasons) != 2 || reasons[0] != "In the quiet period" {
+		t.Fatalf("want 2 progress reports but got %v\n", reasons)
+	}
+}
+
+func TestWaitForBuildStartCancelled(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":42,"cancelled":true}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	_, err := jenkinsClient.WaitForBuildStart(context.Background(), QueueItem{ID: 42}, fastPolls)
+	if !errors.Is(err, ErrQueueItemCancelled) {
+		t.Fatalf("want ErrQueueItemCancelled but got %v\n", err)
+	}
+}
+
+func TestWaitForBuildStartContextDone(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":42,"why":"Waiting for next available executor"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := jenkinsClient.WaitForBuildStart(ctx, QueueItem{ID: 42}, fastPolls)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded but got %v\n", err)
+	}
+}
+
+func TestWaitForBuildStartForgottenItem(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	_, err := jenkinsClient.WaitForBuildStart(context.Background(), QueueItem{ID: 42}, fastPolls)
+	if !IsNotFound(err) || !strings.Contains(err.Error(), "queue/item/42") {
+		t.Fatalf("want a not-found error for the queue item but got %v\n", err)
+	}
+}
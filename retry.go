@@ -0,0 +1,179 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy decides which failed requests are retried, how often, and how long to wait in between.  Only
+// network errors and responses with a 5xx or 429 status are retried; other client errors, such as a 404, would
+// fail the same way again, and so would network errors caused by the configuration, such as an untrusted
+// certificate.  A Retry-After header sent by Jenkins is honored when it asks for a longer wait than the backoff;
+// if it asks for more than MaxBackoff, the request fails instead.  The context of the call bounds the total time
+// spent.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.  Values below 1 mean a single attempt.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the second attempt.  It doubles for every attempt after that.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the backoff and the wait Jenkins may ask for with Retry-After.  Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of each backoff that is randomized so that many clients failing
+	// together do not retry in lockstep.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used by clients not given WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.2,
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for the client.  Use RetryPolicy{MaxAttempts: 1} to disable retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = &policy
+	}
+}
+
+// backoff returns how long to wait after the given failed attempt, counting from 1.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	d := policy.InitialBackoff
+	for i := 1; i < attempt && (policy.MaxBackoff == 0 || d < policy.MaxBackoff); i++ {
+		d *= 2
+	}
+	if policy.MaxBackoff > 0 && d > policy.MaxBackoff {
+		d = policy.MaxBackoff
+	}
+	if policy.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * policy.Jitter * float64(d))
+	}
+	return d
+}
+
+// wait returns how long to wait after the given failed attempt ended with err.  It returns false if Jenkins asked
+// for a longer wait than MaxBackoff, in which case the attempt is not retried.
+func (policy RetryPolicy) wait(attempt int, err error) (time.Duration, bool) {
+	d := policy.backoff(attempt)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		if policy.MaxBackoff > 0 && apiErr.RetryAfter > policy.MaxBackoff {
+			return 0, false
+		}
+		d = apiErr.RetryAfter
+	}
+	return d, true
+}
+
+// retryPolicy returns the policy in effect for the client.
+func (client Client) retryPolicy() RetryPolicy {
+	if client.retry == nil {
+		return DefaultRetryPolicy
+	}
+	return *client.retry
+}
+
+// try calls the idempotent work until it succeeds or the retry policy gives up.  Each call is passed a context
+// that carries the attempt number.
+func (client Client) try(ctx context.Context, work func(ctx context.Context) error) error {
+	return client.retryWork(ctx, work, nil)
+}
+
+// tryNonIdempotent is try for work that must not be repeated once it has taken effect.  Before retrying a
+// failure that could have been applied by Jenkins anyway, tookEffect is asked whether it was.  If so,
+// tryNonIdempotent succeeds without another attempt.  If that cannot be determined, the failure is returned.
+func (client Client) tryNonIdempotent(ctx context.Context, work func(ctx context.Context) error, tookEffect func() (bool, error)) error {
+	return client.retryWork(ctx, work, tookEffect)
+}
+
+func (client Client) retryWork(ctx context.Context, work func(ctx context.Context) error, tookEffect func() (bool, error)) error {
+	policy := client.retryPolicy()
+	for attempt := 1; ; attempt++ {
+		err := work(withAttempt(ctx, attempt))
+		if err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+		wait, ok := policy.wait(attempt, err)
+		if !ok {
+			return err
+		}
+		client.log().LogAttrs(ctx, slog.LevelWarn, "retrying Jenkins request",
+			slog.String("job", jobFrom(ctx)),
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", wait),
+			slog.String("error", redactError(err)),
+		)
+		if client.instrumentation != nil {
+			client.instrumentation.RetryScheduled(RetryInfo{JobName: jobFrom(ctx), Attempt: attempt, Backoff: wait, Err: err})
+		}
+		if e := sleepContext(ctx, wait); e != nil {
+			return err
+		}
+		if tookEffect != nil && mayHaveTakenEffect(err) {
+			done, e := tookEffect()
+			if e != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+// isRetryable reports whether a request that failed with err might succeed when sent again.  Timeouts of a single
+// attempt, as set by WithTimeout, are retried; retryWork stops on its own once the caller's context is done.
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrBuildMayHaveBeenQueued) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	// *url.Error is itself a net.Error, so it is looked into: TLS verification failures and unsupported schemes
+	// come wrapped in one as well.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// mayHaveTakenEffect reports whether Jenkins might have acted on a request that failed with err.  A 429 or 503
+// means the request was turned away before being processed.
+func mayHaveTakenEffect(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode != http.StatusServiceUnavailable
+	}
+	return true
+}
+
+// sleepContext waits for d to elapse or ctx to be done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
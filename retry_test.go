@@ -0,0 +1,241 @@
+package jenkins
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetries = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+func TestNotFoundIsNotRetried(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	if _, err := jenkinsClient.GetJobConfig("thejob"); !IsNotFound(err) {
+		t.Fatalf("Want a not-found error but got %v\n", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Want 1 attempt but got %d\n", calls)
+	}
+}
+
+func TestServerErrorIsRetried(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("Want 3 attempts but got %d\n", n)
+	}
+}
+
+func TestRetryAfterHonored(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"jobs":[]}`))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	start := time.Now()
+	if _, err := jenkinsClient.GetJobs(); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("Want to wait the 1s asked for by Retry-After but waited %v\n", elapsed)
+	}
+}
+
+func TestRetryAfterBeyondMaxBackoffGivesUp(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}))
+	start := time.Now()
+	_, err := jenkinsClient.GetJobs()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Want the 429 *APIError but got %v\n", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Want to give up at once but waited %v\n", elapsed)
+	}
+	if calls != 1 {
+		t.Fatalf("Want 1 attempt but got %d\n", calls)
+	}
+}
+
+func TestConfigurationErrorsAreNotRetried(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer testServer.Close()
+
+	// The test server's certificate is not trusted by the default client.
+	untrusted, _ := url.Parse(testServer.URL)
+	unsupported, _ := url.Parse("ftp://jenkins.example.com")
+	for _, baseURL := range []*url.URL{untrusted, unsupported} {
+		recorder := &recordingInstrumentation{}
+		jenkinsClient := NewClient(baseURL, "u", "p", WithRetryPolicy(fastRetries), WithInstrumentation(recorder))
+		if _, err := jenkinsClient.GetJobs(); err == nil {
+			t.Fatalf("Want an error for %s but got none\n", baseURL)
+		}
+		if len(recorder.retries) != 0 {
+			t.Fatalf("Want no retries for %s but got %d\n", baseURL, len(recorder.retries))
+		}
+	}
+}
+
+func TestTimedOutAttemptsAreRetried(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries), WithTimeout(50*time.Millisecond))
+	if _, err := jenkinsClient.GetJobs(); err == nil {
+		t.Fatalf("Want a timeout error but got none\n")
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("Want 3 attempts but got %d\n", n)
+	}
+}
+
+func TestSingleAttemptPolicy(t *testing.T) {
+	var calls int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	if _, err := jenkinsClient.GetLastBuild("thejob"); err == nil {
+		t.Fatalf("Want an error but got none\n")
+	}
+	if calls != 1 {
+		t.Fatalf("Want 1 attempt but got %d\n", calls)
+	}
+}
+
+func TestCreateJobNotRepeatedOnceApplied(t *testing.T) {
+	var posts int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/createItem":
+			// The job gets created, but the response is lost to a proxy error.
+			atomic.AddInt32(&posts, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		case r.Method == "GET" && r.URL.Path == "/job/job-name/api/json":
+			w.Write([]byte(`{"name":"job-name"}`))
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	if err := jenkinsClient.CreateJob("job-name", fooJob); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if posts != 1 {
+		t.Fatalf("Want 1 POST but got %d\n", posts)
+	}
+}
+
+func TestCreateJobRetriedWhenThrottled(t *testing.T) {
+	var posts int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("Not expecting an existence check after a 503\n")
+		}
+		if atomic.AddInt32(&posts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	if err := jenkinsClient.CreateJob("job-name", fooJob); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if posts != 2 {
+		t.Fatalf("Want 2 POSTs but got %d\n", posts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		if got := policy.backoff(attempt + 1); got != want {
+			t.Fatalf("Want backoff %v after attempt %d but got %v\n", want, attempt+1, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("Want jittered backoff within [50ms, 100ms] but got %v\n", got)
+		}
+	}
+}
+
+func TestRetryAfterParsing(t *testing.T) {
+	if d := parseRetryAfter("120"); d != 2*time.Minute {
+		t.Fatalf("Want 2m but got %v\n", d)
+	}
+	if d := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); d < 59*time.Minute {
+		t.Fatalf("Want about 1h but got %v\n", d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Fatalf("Want 0 but got %v\n", d)
+	}
+	if isRetryable(errors.New("malformed config")) {
+		t.Fatalf("Want decoding errors not to be retried\n")
+	}
+}
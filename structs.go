@@ -1,8 +1,13 @@
 package jenkins
 
 import (
+	"context"
 	"encoding/xml"
+	"io"
+	"log/slog"
+	"net/http"
 	"net/url"
+	"time"
 )
 
 type JobType int
@@ -13,6 +18,37 @@ const (
 	Unknown
 )
 
+// SummaryPhase is the step at which summarizing a job failed.
+type SummaryPhase string
+
+const (
+	PhaseFetch       SummaryPhase = "fetch"        // the job config could not be retrieved
+	PhaseParse       SummaryPhase = "parse"        // the job config is not a well-formed config of its job type
+	PhaseUnknownType SummaryPhase = "unknown type" // the job is of a type that cannot be summarized
+)
+
+// Permalink names a build relative to the job's history, such as its last successful build.
+type Permalink string
+
+const (
+	PermalinkLastBuild             Permalink = "lastBuild"
+	PermalinkLastSuccessfulBuild   Permalink = "lastSuccessfulBuild"
+	PermalinkLastStableBuild       Permalink = "lastStableBuild"
+	PermalinkLastFailedBuild       Permalink = "lastFailedBuild"
+	PermalinkLastUnstableBuild     Permalink = "lastUnstableBuild"
+	PermalinkLastUnsuccessfulBuild Permalink = "lastUnsuccessfulBuild"
+	PermalinkLastCompletedBuild    Permalink = "lastCompletedBuild"
+)
+
+// ApplyResult is what ApplyJob did to make a job match its config.
+type ApplyResult string
+
+const (
+	ApplyCreated   ApplyResult = "created"   // the job did not exist and was created
+	ApplyUpdated   ApplyResult = "updated"   // the job existed and its config was replaced
+	ApplyUnchanged ApplyResult = "unchanged" // the job already had the config, so nothing was written
+)
+
 type (
 	Jenkins interface {
 		GetJobs() (map[string]JobDescriptor, error)
@@ -20,21 +56,79 @@ type (
 		GetJobSummaries() ([]JobSummary, error)
 		GetJobSummariesFromFilesystem(root string) ([]JobSummary, error)
 		GetLastBuild(jobName string) (LastBuild, error)
+		GetBuild(jobName string, number int) (Build, error)
+		GetBuildByPermalink(jobName string, permalink Permalink) (Build, error)
+		GetConsoleText(jobName string, number int) (string, error)
+		ListBuilds(jobName string, opts ListBuildsOptions) *BuildIterator
 		CreateJob(jobName, jobConfigXML string) error
 		DeleteJob(jobName string) error
+		JobExists(jobName string) (bool, error)
+		ValidateJobName(jobName string) error
+		TriggerBuild(jobName string, params map[string]string) (QueueItem, error)
+		GetQueueItem(id int64) (QueueItem, error)
+		UpdateJobConfig(jobName, jobConfigXML string) error
+		UpdateMavenJobConfig(jobName string, config JobConfig) error
+		UpdateFreeStyleJobConfig(jobName string, config FreeStyleJobConfig) error
+		ApplyJob(jobName, jobConfigXML string) (ApplyResult, error)
+		CopyJob(srcJobName, dstJobName string) error
+		RenameJob(oldJobName, newJobName string) error
+		EnableJob(jobName string) error
+		DisableJob(jobName string) error
+		GetJobsRecursive() (map[string]JobDescriptor, error)
+
+		GetJobsContext(ctx context.Context) (map[string]JobDescriptor, error)
+		GetJobsRecursiveContext(ctx context.Context) (map[string]JobDescriptor, error)
+		GetJobConfigContext(ctx context.Context, jobName string) (JobConfig, error)
+		GetJobSummariesContext(ctx context.Context) ([]JobSummary, error)
+		GetJobSummaryReport(ctx context.Context, opts SummaryOptions) (JobSummaryReport, error)
+		GetJobSummaryReportFromFilesystem(root string, opts SummaryOptions) (JobSummaryReport, error)
+		GetLastBuildContext(ctx context.Context, jobName string) (LastBuild, error)
+		GetBuildContext(ctx context.Context, jobName string, number int) (Build, error)
+		GetBuildByPermalinkContext(ctx context.Context, jobName string, permalink Permalink) (Build, error)
+		GetConsoleTextContext(ctx context.Context, jobName string, number int) (string, error)
+		StreamConsole(ctx context.Context, jobName string, number int, w io.Writer, opts ...WaitOption) error
+		ListBuildsContext(ctx context.Context, jobName string, opts ListBuildsOptions) *BuildIterator
+		CreateJobContext(ctx context.Context, jobName, jobConfigXML string) error
+		DeleteJobContext(ctx context.Context, jobName string) error
+		JobExistsContext(ctx context.Context, jobName string) (bool, error)
+		ValidateJobNameContext(ctx context.Context, jobName string) error
+		TriggerBuildContext(ctx context.Context, jobName string, params map[string]string) (QueueItem, error)
+		TriggerBuildWithOptions(ctx context.Context, jobName string, opts TriggerOptions) (QueueItem, error)
+		GetQueueItemContext(ctx context.Context, id int64) (QueueItem, error)
+		WaitForBuildStart(ctx context.Context, item QueueItem, opts ...WaitOption) (QueueExecutable, error)
+		WaitForBuild(ctx context.Context, jobName string, number int, opts ...WaitOption) (Build, error)
+		UpdateJobConfigContext(ctx context.Context, jobName, jobConfigXML string) error
+		UpdateMavenJobConfigContext(ctx context.Context, jobName string, config JobConfig) error
+		UpdateFreeStyleJobConfigContext(ctx context.Context, jobName string, config FreeStyleJobConfig) error
+		ApplyJobContext(ctx context.Context, jobName, jobConfigXML string) (ApplyResult, error)
+		CopyJobContext(ctx context.Context, srcJobName, dstJobName string) error
+		RenameJobContext(ctx context.Context, oldJobName, newJobName string) error
+		EnableJobContext(ctx context.Context, jobName string) error
+		DisableJobContext(ctx context.Context, jobName string) error
 	}
 
 	Client struct {
-		baseURL  *url.URL
-		userName string
-		password string
+		baseURL    *url.URL
+		auth       Authenticator
+		httpClient *http.Client
+		userAgent  string
+		crumbs     *crumbCache
+		retry      *RetryPolicy
+		limiter    *limiter
+
+		summaryWorkers int
+		logger         *slog.Logger
+
+		instrumentation Instrumentation
 		Jenkins
 	}
 
 	JobDescriptor struct {
-		Name  string `json:"name"`
-		Color string `json:"color"`
-		URL   string `json:"url"`
+		Name   string  `json:"name"`
+		Color  string  `json:"color"`
+		URL    string  `json:"url"`
+		Path   JobPath `json:"-"` // the full path of the job, including its folders
+		Folder bool    `json:"-"` // only known for descriptors from GetJobsRecursive
 	}
 
 	Jobs struct {
@@ -47,20 +141,35 @@ type (
 		SCM        Scm        `xml:"scm"`
 		Publishers Publishers `xml:"publishers"`
 		RootModule RootModule `xml:"rootModule"`
-		JobName    string
+		Disabled   bool       `xml:"disabled"`
+		JobName    string     `xml:"-"`
+		Attrs      []xml.Attr `xml:",any,attr"`
+		Other      []Element  `xml:",any"`
 	}
 
 	// Freestyle project
 	FreeStyleJobConfig struct {
-		XMLName xml.Name `xml:"project"`
-		SCM     Scm      `xml:"scm"`
-		JobName string
+		XMLName  xml.Name   `xml:"project"`
+		SCM      Scm        `xml:"scm"`
+		Disabled bool       `xml:"disabled"`
+		JobName  string     `xml:"-"`
+		Attrs    []xml.Attr `xml:",any,attr"`
+		Other    []Element  `xml:",any"`
+	}
+
+	// An element of a job config that the config structs do not model.  Decoding keeps these so that a config
+	// written back with UpdateMavenJobConfig or UpdateFreeStyleJobConfig loses none of its settings.
+	Element struct {
+		XMLName xml.Name
+		Attrs   []xml.Attr `xml:",any,attr"`
+		Inner   string     `xml:",innerxml"`
 	}
 
 	// Model of both Maven and Freestyle job types
 	JobSummary struct {
 		JobDescriptor JobDescriptor
 		JobType       JobType
+		Disabled      bool
 		GitURL        string // the use of this field is deprecated
 		Branch        string // the use of this field is deprecated
 	}
@@ -70,42 +179,70 @@ type (
 		Class             string            `xml:"class,attr"`
 		UserRemoteConfigs UserRemoteConfigs `xml:"userRemoteConfigs"`
 		Branches          Branches          `xml:"branches"`
+		Attrs             []xml.Attr        `xml:",any,attr"`
+		Other             []Element         `xml:",any"`
 	}
 
 	Publishers struct {
 		XMLName            xml.Name            `xml:"publishers"`
 		RedeployPublishers []RedeployPublisher `xml:"hudson.maven.RedeployPublisher"`
+		Other              []Element           `xml:",any"`
 	}
 
 	RedeployPublisher struct {
-		XMLName xml.Name `xml:"hudson.maven.RedeployPublisher"`
-		URL     string   `xml:"url"`
+		XMLName xml.Name   `xml:"hudson.maven.RedeployPublisher"`
+		URL     string     `xml:"url"`
+		Attrs   []xml.Attr `xml:",any,attr"`
+		Other   []Element  `xml:",any"`
 	}
 
 	UserRemoteConfigs struct {
 		XMLName          xml.Name           `xml:"userRemoteConfigs"`
 		UserRemoteConfig []UserRemoteConfig `xml:"hudson.plugins.git.UserRemoteConfig"`
+		Other            []Element          `xml:",any"`
 	}
 
 	UserRemoteConfig struct {
-		XMLName xml.Name `xml:"hudson.plugins.git.UserRemoteConfig"`
-		URL     string   `xml:"url"`
+		XMLName xml.Name  `xml:"hudson.plugins.git.UserRemoteConfig"`
+		URL     string    `xml:"url"`
+		Other   []Element `xml:",any"`
 	}
 
 	Branches struct {
-		XMLName xml.Name `xml:"branches"`
-		Branch  []Branch `xml:"hudson.plugins.git.BranchSpec"`
+		XMLName xml.Name  `xml:"branches"`
+		Branch  []Branch  `xml:"hudson.plugins.git.BranchSpec"`
+		Other   []Element `xml:",any"`
 	}
 
 	Branch struct {
-		XMLName xml.Name `xml:"hudson.plugins.git.BranchSpec"`
-		Name    string   `xml:"name"`
+		XMLName xml.Name  `xml:"hudson.plugins.git.BranchSpec"`
+		Name    string    `xml:"name"`
+		Other   []Element `xml:",any"`
 	}
 
 	RootModule struct {
-		XMLName    xml.Name `xml:"rootModule"`
-		GroupID    string   `xml:"groupId"`
-		ArtifactID string   `xml:"artifactId"`
+		XMLName    xml.Name  `xml:"rootModule"`
+		GroupID    string    `xml:"groupId"`
+		ArtifactID string    `xml:"artifactId"`
+		Other      []Element `xml:",any"`
+	}
+
+	// Why a job is missing from a JobSummaryReport
+	JobSummaryError struct {
+		JobName string // empty if not even the name of the job could be determined
+		Phase   SummaryPhase
+		Err     error
+	}
+
+	// Summaries of jobs, together with the jobs that could not be summarized
+	JobSummaryReport struct {
+		Summaries []JobSummary
+		Errors    []JobSummaryError
+	}
+
+	SummaryOptions struct {
+		// FailFast stops summarizing at the first job that cannot be summarized, and returns its error.
+		FailFast bool
 	}
 
 	LastBuild struct {
@@ -113,4 +250,117 @@ type (
 		TimestampMillis int64  `json:"timestamp"`
 		URL             string `json:"url"`
 	}
+
+	// A handle on a build request waiting in the Jenkins build queue.  TriggerBuild only fills in ID and URL;
+	// GetQueueItem fills in the state of the item as well.
+	QueueItem struct {
+		ID  int64  `json:"id"`
+		URL string `json:"-"`
+
+		Why                string           `json:"why"` // why the item is still waiting, if it is
+		Blocked            bool             `json:"blocked"`
+		Buildable          bool             `json:"buildable"`
+		Stuck              bool             `json:"stuck"`
+		Cancelled          bool             `json:"cancelled"`
+		InQueueSinceMillis int64            `json:"inQueueSince"`
+		Executable         *QueueExecutable `json:"executable"` // the build, once the item has left the queue
+	}
+
+	// The build a queue item became
+	QueueExecutable struct {
+		Number int    `json:"number"`
+		URL    string `json:"url"`
+	}
+
+	// A build of a job
+	Build struct {
+		Number            int
+		URL               string
+		DisplayName       string
+		Description       string
+		QueueID           int64
+		Building          bool
+		Result            string // empty while the build is running
+		Timestamp         time.Time
+		Duration          time.Duration // zero while the build is running
+		EstimatedDuration time.Duration
+		BuiltOn           string // the name of the agent, or empty for the built-in node
+		Causes            []BuildCause
+		Parameters        []BuildParameter
+		Artifacts         []Artifact
+		ChangeSet         []Change
+	}
+
+	// Why a build was started
+	BuildCause struct {
+		ShortDescription string // for example "Started by user Jane Doe"
+		UserID           string // for builds started by a user
+		UserName         string
+		UpstreamProject  string // for builds started by another build
+		UpstreamBuild    int
+		UpstreamURL      string
+	}
+
+	// The value of a build parameter.  Values that are not strings, such as booleans, are given in their JSON
+	// encoding.  File parameters have no value.
+	BuildParameter struct {
+		Name  string
+		Value string
+	}
+
+	// A file archived by a build
+	Artifact struct {
+		FileName     string
+		RelativePath string // the path of the file relative to the artifacts of the build
+		URL          string
+	}
+
+	// A source change that went into a build
+	Change struct {
+		CommitID      string
+		Message       string
+		Author        string
+		Timestamp     time.Time
+		AffectedPaths []string
+	}
+
+	ListBuildsOptions struct {
+		// PageSize is the number of builds fetched per request.  Zero means DefaultBuildPageSize.
+		PageSize int
+
+		// Result, if not empty, selects only finished builds with this result, such as "FAILURE".
+		Result string
+
+		// Since and Until, if not zero, select only builds started at or after Since and before Until.
+		Since time.Time
+		Until time.Time
+	}
+
+	// The state of a running build, as reported while waiting for it
+	BuildProgress struct {
+		Number            int
+		Building          bool
+		Elapsed           time.Duration
+		EstimatedDuration time.Duration
+	}
+
+	TriggerOptions struct {
+		// Parameters are the values of the job's build parameters.  A nil map triggers the build through /build,
+		// which is for jobs without parameters.  A non-nil map, even an empty one, triggers it through
+		// /buildWithParameters, where parameters that are not given take their default values.
+		Parameters map[string]string
+
+		// Files are the values of the job's file parameters.  They imply /buildWithParameters.
+		Files []FileParameter
+
+		// Token is the authentication token configured for triggering the job remotely, if any.
+		Token string
+	}
+
+	// The value of a file parameter of a build
+	FileParameter struct {
+		Name     string // the name of the build parameter
+		FileName string // the name of the file as seen by the build
+		Content  io.Reader
+	}
 )
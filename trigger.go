@@ -0,0 +1,160 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// TriggerBuild is TriggerBuildContext with a background context.
+func (client Client) TriggerBuild(jobName string, params map[string]string) (QueueItem, error) {
+	return client.TriggerBuildContext(context.Background(), jobName, params)
+}
+
+// TriggerBuildContext queues a build of the named job with the given parameters, which may be nil for jobs
+// without parameters, and returns a handle on the queue item.  See TriggerOptions.
+func (client Client) TriggerBuildContext(ctx context.Context, jobName string, params map[string]string) (QueueItem, error) {
+	return client.TriggerBuildWithOptions(ctx, jobName, TriggerOptions{Parameters: params})
+}
+
+// TriggerBuildWithOptions queues a build of the named job and returns a handle on the queue item, taken from the
+// Location header of Jenkins' response.  The queue item becomes a build once an executor is free.  A failed
+// attempt is only retried if Jenkins turned the request away without acting on it, since the same build must
+// not be queued twice.  Other failures that might have queued the build match ErrBuildMayHaveBeenQueued.
+func (client Client) TriggerBuildWithOptions(ctx context.Context, jobName string, opts TriggerOptions) (QueueItem, error) {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return QueueItem{}, err
+	}
+
+	resource := "build"
+	if opts.Parameters != nil || len(opts.Files) > 0 {
+		resource = "buildWithParameters"
+	}
+	var query url.Values
+	if opts.Token != "" {
+		query = url.Values{"token": {opts.Token}}
+	}
+	body, contentType, err := triggerRequestBody(opts)
+	if err != nil {
+		return QueueItem{}, err
+	}
+
+	var item QueueItem
+	work := func(ctx context.Context) error {
+		// Once the request is on the wire, Jenkins may queue the build whatever happens to the response.
+		var sent atomic.Bool
+		ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			WroteRequest: func(info httptrace.WroteRequestInfo) {
+				if info.Err == nil {
+					sent.Store(true)
+				}
+			},
+		})
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(jobName, resource, query), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-type", contentType)
+
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			if sent.Load() {
+				return fmt.Errorf("%w: %w", ErrBuildMayHaveBeenQueued, err)
+			}
+			return err
+		}
+		if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusFound {
+			return outcomeUnknown(newAPIError(req, response, data, jobName))
+		}
+		item, err = queueItemFromLocation(response.Header.Get("Location"))
+		if err != nil {
+			return fmt.Errorf("jenkins: triggering a build of %s: %w", jobName, err)
+		}
+		return nil
+	}
+	if err := client.try(ctx, work); err != nil {
+		return QueueItem{}, err
+	}
+	return item, nil
+}
+
+// outcomeUnknown marks the error response err with ErrBuildMayHaveBeenQueued, which stops it from being retried,
+// if it would otherwise be retried although Jenkins may have acted on the request.
+func outcomeUnknown(err error) error {
+	if isRetryable(err) && mayHaveTakenEffect(err) {
+		return fmt.Errorf("%w: %w", ErrBuildMayHaveBeenQueued, err)
+	}
+	return err
+}
+
+// triggerRequestBody encodes the parameters of opts as a form, or as a multipart form if there are files.  The
+// body is read into memory so that it can be sent again.
+func triggerRequestBody(opts TriggerOptions) ([]byte, string, error) {
+	if len(opts.Files) == 0 {
+		form := url.Values{}
+		for name, value := range opts.Parameters {
+			form.Set(name, value)
+		}
+		return []byte(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	names := make([]string, 0, len(opts.Parameters))
+	for name := range opts.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writer.WriteField(name, opts.Parameters[name]); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, file := range opts.Files {
+		part, err := writer.CreateFormFile(file.Name, file.FileName)
+		if err != nil {
+			return nil, "", err
+		}
+		if file.Content != nil {
+			if _, err := io.Copy(part, file.Content); err != nil {
+				return nil, "", fmt.Errorf("jenkins: reading file parameter %s: %w", file.Name, err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+// queueItemFromLocation returns the queue item at location, a URL such as http://jenkins/queue/item/42/.
+func queueItemFromLocation(location string) (QueueItem, error) {
+	if location == "" {
+		return QueueItem{}, errors.New("no queue item location in the response")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return QueueItem{}, fmt.Errorf("queue item location %q: %w", location, err)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	n := len(segments)
+	if n < 3 || segments[n-3] != "queue" || segments[n-2] != "item" {
+		return QueueItem{}, fmt.Errorf("location %q is not a queue item", location)
+	}
+	id, err := strconv.ParseInt(segments[n-1], 10, 64)
+	if err != nil {
+		return QueueItem{}, fmt.Errorf("queue item location %q: %w", location, err)
+	}
+	return QueueItem{ID: id, URL: location}, nil
+}
@@ -0,0 +1,221 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTriggerBuild(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		if r.URL.Path != "/job/team/job/service/build" {
+			t.Fatalf("wanted URL path /job/team/job/service/build but found %s\n", r.URL.Path)
+		}
+		w.Header().Set("Location", "http://"+r.Host+"/queue/item/42/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	item, err := jenkinsClient.TriggerBuild("team/service", nil)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if item.ID != 42 || !strings.HasSuffix(item.URL, "/queue/item/42/") {
+		t.Fatalf("want queue item 42 but got %+v\n", item)
+	}
+}
+
+func TestTriggerBuildWithParameters(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/service/buildWithParameters" {
+			t.Fatalf("wanted URL path /job/service/buildWithParameters but found %s\n", r.URL.Path)
+		}
+		if r.URL.Query().Get("token") != "s3cret" {
+			t.Fatalf("want token s3cret but got %s\n", r.URL.RawQuery)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("unexpected error: %v\n", err)
+		}
+		if r.PostForm.Get("BRANCH") != "feature/x" {
+			t.Fatalf("want BRANCH feature/x but got %v\n", r.PostForm)
+		}
+		w.Header().Set("Location", "/queue/item/7/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	item, err := jenkinsClient.TriggerBuildWithOptions(context.Background(), "service", TriggerOptions{Parameters: map[string]string{"BRANCH": "feature/x"}, Token: "s3cret"})
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if item.ID != 7 {
+		t.Fatalf("want queue item 7 but got %+v\n", item)
+	}
+}
+
+func TestTriggerBuildWithFile(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("unexpected error: %v\n", err)
+		}
+		if r.MultipartForm.Value["BRANCH"][0] != "main" {
+			t.Fatalf("want BRANCH main but got %v\n", r.MultipartForm.Value)
+		}
+		file, header, err := r.FormFile("settings.xml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v\n", err)
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "settings.xml" || string(content) != "<settings/>" {
+			t.Fatalf("unexpected file %s with content %s\n", header.Filename, string(content))
+		}
+		w.Header().Set("Location", "/queue/item/8/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	opts := TriggerOptions{
+		Parameters: map[string]string{"BRANCH": "main"},
+		Files:      []FileParameter{{Name: "settings.xml", FileName: "settings.xml", Content: strings.NewReader("<settings/>")}},
+	}
+	if _, err := jenkinsClient.TriggerBuildWithOptions(context.Background(), "service", opts); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+}
+
+func TestTriggerBuildIsNotRepeated(t *testing.T) {
+	calls := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	_, err := jenkinsClient.TriggerBuild("service", nil)
+	if !errors.Is(err, ErrBuildMayHaveBeenQueued) {
+		t.Fatalf("want ErrBuildMayHaveBeenQueued but got %v\n", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("want the 502 *APIError but got %v\n", err)
+	}
+	if calls != 1 {
+		t.Fatalf("want 1 call but got %d\n", calls)
+	}
+}
+
+func TestTimedOutTriggerMayHaveBeenQueued(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries), WithTimeout(50*time.Millisecond))
+	_, err := jenkinsClient.TriggerBuild("service", nil)
+	if !errors.Is(err, ErrBuildMayHaveBeenQueued) {
+		t.Fatalf("want ErrBuildMayHaveBeenQueued but got %v\n", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("want 1 call but got %d\n", n)
+	}
+}
+
+func TestTriggerBuildRetriesWhenTurnedAway(t *testing.T) {
+	calls := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Location", "/queue/item/9/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	item, err := jenkinsClient.TriggerBuild("service", nil)
+	if err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if item.ID != 9 || calls != 2 {
+		t.Fatalf("want queue item 9 after 2 calls but got %+v after %d\n", item, calls)
+	}
+}
+
+func TestTriggerBuildWithoutLocation(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if _, err := jenkinsClient.TriggerBuild("service", nil); err == nil {
+		t.Fatalf("want an error for a response without a queue item location\n")
+	}
+}
+
+func TestTriggerBuildDoesNotLeakToken(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer testServer.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	recorder := &recordingInstrumentation{}
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries), WithLogger(logger), WithInstrumentation(recorder))
+	_, err := jenkinsClient.TriggerBuildWithOptions(context.Background(), "svc", TriggerOptions{Token: "s3cret"})
+	if err == nil {
+		t.Fatalf("want an error but got none\n")
+	}
+	if strings.Contains(err.Error(), "s3cret") {
+		t.Fatalf("token leaked into the error: %v\n", err)
+	}
+	if !strings.Contains(buf.String(), "retrying Jenkins request") {
+		t.Fatalf("want a retry log line but got %s\n", buf.String())
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Fatalf("token leaked into the log: %s\n", buf.String())
+	}
+	if len(recorder.retries) == 0 {
+		t.Fatalf("want retries reported to the instrumentation\n")
+	}
+	for _, info := range recorder.retries {
+		if strings.Contains(info.Err.Error(), "s3cret") {
+			t.Fatalf("token leaked into RetryInfo.Err: %v\n", info.Err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+)
+
+// UpdateJobConfig is UpdateJobConfigContext with a background context.
+func (client Client) UpdateJobConfig(jobName, jobConfigXML string) error {
+	return client.UpdateJobConfigContext(context.Background(), jobName, jobConfigXML)
+}
+
+// UpdateJobConfigContext replaces the config of an existing Jenkins job with the given XML job config.  Unlike
+// deleting and recreating the job, this keeps its build history.  Posting the same config twice has the same
+// effect as posting it once, so failed attempts are retried.
+func (client Client) UpdateJobConfigContext(ctx context.Context, jobName, jobConfigXML string) error {
+	ctx = withJob(ctx, jobName)
+	if err := checkJobPath(jobName); err != nil {
+		return err
+	}
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", client.resourceURL(jobName, "config.xml", nil), bytes.NewBuffer([]byte(jobConfigXML)))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-type", "application/xml")
+
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
+		}
+		return nil
+	}
+	return client.try(ctx, work)
+}
+
+// UpdateMavenJobConfig is UpdateMavenJobConfigContext with a background context.
+func (client Client) UpdateMavenJobConfig(jobName string, config JobConfig) error {
+	return client.UpdateMavenJobConfigContext(context.Background(), jobName, config)
+}
+
+// UpdateMavenJobConfigContext replaces the config of an existing Maven job with the given config, typically one
+// obtained from GetJobConfig and then modified.  Settings the config structs do not model are written back as
+// they were read, after the modeled ones.
+func (client Client) UpdateMavenJobConfigContext(ctx context.Context, jobName string, config JobConfig) error {
+	configXML, err := marshalJobConfig(config)
+	if err != nil {
+		return err
+	}
+	return client.UpdateJobConfigContext(ctx, jobName, configXML)
+}
+
+// UpdateFreeStyleJobConfig is UpdateFreeStyleJobConfigContext with a background context.
+func (client Client) UpdateFreeStyleJobConfig(jobName string, config FreeStyleJobConfig) error {
+	return client.UpdateFreeStyleJobConfigContext(context.Background(), jobName, config)
+}
+
+// UpdateFreeStyleJobConfigContext replaces the config of an existing Freestyle job with the given config.  See
+// UpdateMavenJobConfigContext.
+func (client Client) UpdateFreeStyleJobConfigContext(ctx context.Context, jobName string, config FreeStyleJobConfig) error {
+	configXML, err := marshalJobConfig(config)
+	if err != nil {
+		return err
+	}
+	return client.UpdateJobConfigContext(ctx, jobName, configXML)
+}
+
+// marshalJobConfig encodes a config struct as a config.xml document.
+func marshalJobConfig(config interface{}) (string, error) {
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data), nil
+}
+
+// The MarshalXML methods below leave out parts of a config that were neither in the decoded document nor filled
+// in since.  Jenkins rejects configs with elements it does not expect, such as Git branches in a Subversion scm,
+// or an scm without a class.
+
+// MarshalXML implements xml.Marshaler.
+func (s Scm) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if s.absent() {
+		return nil
+	}
+	type plain Scm
+	return e.EncodeElement(plain(s), start)
+}
+
+func (s Scm) absent() bool {
+	return s.XMLName.Local == "" && s.Class == "" && len(s.Attrs) == 0 && len(s.Other) == 0 &&
+		s.UserRemoteConfigs.absent() && s.Branches.absent()
+}
+
+// MarshalXML implements xml.Marshaler.
+func (c UserRemoteConfigs) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if c.absent() {
+		return nil
+	}
+	type plain UserRemoteConfigs
+	return e.EncodeElement(plain(c), start)
+}
+
+func (c UserRemoteConfigs) absent() bool {
+	return c.XMLName.Local == "" && len(c.UserRemoteConfig) == 0 && len(c.Other) == 0
+}
+
+// MarshalXML implements xml.Marshaler.
+func (b Branches) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if b.absent() {
+		return nil
+	}
+	type plain Branches
+	return e.EncodeElement(plain(b), start)
+}
+
+func (b Branches) absent() bool {
+	return b.XMLName.Local == "" && len(b.Branch) == 0 && len(b.Other) == 0
+}
+
+// MarshalXML implements xml.Marshaler.
+func (m RootModule) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if m.absent() {
+		return nil
+	}
+	type plain RootModule
+	return e.EncodeElement(plain(m), start)
+}
+
+func (m RootModule) absent() bool {
+	return m.XMLName.Local == "" && m.GroupID == "" && m.ArtifactID == "" && len(m.Other) == 0
+}
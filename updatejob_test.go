@@ -0,0 +1,153 @@
+package jenkins
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUpdateJobConfig(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		if r.URL.Path != "/job/team/job/service/config.xml" {
+			t.Fatalf("wanted URL path /job/team/job/service/config.xml but found %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Content-type") != "application/xml" {
+			t.Fatalf("wanted Content-type header application/xml but found %s\n", r.Header.Get("Content-type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "<project/>" {
+			t.Fatalf("wanted body <project/> but found %s\n", string(body))
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.UpdateJobConfig("team/service", "<project/>"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+}
+
+func TestUpdateJobConfigRetries(t *testing.T) {
+	calls := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "<project/>" {
+			t.Fatalf("wanted body <project/> on attempt %d but found %s\n", calls, string(body))
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p", WithRetryPolicy(fastRetries))
+	if err := jenkinsClient.UpdateJobConfig("service", "<project/>"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+	if calls != 2 {
+		t.Fatalf("want 2 calls but got %d\n", calls)
+	}
+}
+
+func TestUpdateJobConfigNotFound(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	err := jenkinsClient.UpdateJobConfig("service", "<project/>")
+	if !IsNotFound(err) {
+		t.Fatalf("want a not-found error but got %v\n", err)
+	}
+}
+
+func TestUpdateMavenJobConfigPreservesUnmodeledSettings(t *testing.T) {
+	var config JobConfig
+	if err := xml.Unmarshal([]byte(strings.TrimSpace(jobConfig)), &config); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	config.SCM.Branches.Branch[0].Name = "origin/master"
+
+	var posted []byte
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.UpdateMavenJobConfig("service", config); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+
+	if !bytes.HasPrefix(posted, []byte("<?xml")) {
+		t.Fatalf("want an XML declaration but got %s\n", string(posted))
+	}
+	for _, want := range []string{
+		`<maven2-moduleset plugin="maven-plugin@2.6">`,
+		`<scm class="hudson.plugins.git.GitSCM" plugin="git@2.2.4">`,
+		`<name>origin/master</name>`,
+		`<goals>clean install</goals>`,
+		`<recipients>build.failures@example.com</recipients>`,
+		`<groupId>com.example.widgets</groupId>`,
+	} {
+		if !bytes.Contains(posted, []byte(want)) {
+			t.Fatalf("want %s in posted config but got %s\n", want, string(posted))
+		}
+	}
+
+	var roundTripped JobConfig
+	if err := xml.Unmarshal(posted, &roundTripped); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if len(roundTripped.Other) != len(config.Other) {
+		t.Fatalf("want %d unmodeled elements but got %d\n", len(config.Other), len(roundTripped.Other))
+	}
+}
+
+func TestUpdateFreeStyleJobConfigOmitsAbsentGitSettings(t *testing.T) {
+	var config FreeStyleJobConfig
+	if err := xml.Unmarshal([]byte(`<project><scm class="hudson.scm.NullSCM"/><builders/></project>`), &config); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	var posted []byte
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.UpdateFreeStyleJobConfig("service", config); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+
+	want := xml.Header + `<project><scm class="hudson.scm.NullSCM"></scm><disabled>false</disabled><builders></builders></project>`
+	if string(posted) != want {
+		t.Fatalf("want %s but got %s\n", want, string(posted))
+	}
+}
+
+func TestMarshalEmptyJobConfigOmitsScm(t *testing.T) {
+	configXML, err := marshalJobConfig(JobConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if strings.Contains(configXML, "<scm") {
+		t.Fatalf("want no scm element but got %s\n", configXML)
+	}
+}
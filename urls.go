@@ -0,0 +1,168 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// unsafeNameCharacters are the characters Jenkins does not allow in item names.
+const unsafeNameCharacters = `?*/\%!@#$^&|<>[]:;`
+
+// ErrInvalidJobName matches errors for job names Jenkins would not accept.
+var ErrInvalidJobName = errors.New("jenkins: invalid job name")
+
+// InvalidJobNameError explains why a job name was rejected.  It matches ErrInvalidJobName.
+type InvalidJobNameError struct {
+	Name   string
+	Reason string
+}
+
+func (e *InvalidJobNameError) Error() string {
+	return fmt.Sprintf("jenkins: invalid job name %q: %s", e.Name, e.Reason)
+}
+
+func (e *InvalidJobNameError) Is(target error) bool {
+	return target == ErrInvalidJobName
+}
+
+// resourceURL returns the URL of resource, a path relative to the job at jobName such as "config.xml", with the
+// given query parameters.  An empty jobName means the Jenkins root.  The segments of the job path are escaped;
+// resource is used as is.
+func (client Client) resourceURL(jobName, resource string, query url.Values) string {
+	var b strings.Builder
+	if client.baseURL != nil {
+		b.WriteString(strings.TrimSuffix(client.baseURL.String(), "/"))
+	}
+	b.WriteString(JobPath(jobName).urlPath())
+	if resource != "" {
+		b.WriteString("/")
+		b.WriteString(resource)
+	}
+	if len(query) > 0 {
+		b.WriteString("?")
+		b.WriteString(query.Encode())
+	}
+	return b.String()
+}
+
+// checkJobPath checks that jobName can address an existing job.  Jobs created before Jenkins tightened its naming
+// rules may have names checkNewJobName would reject, so only names that cannot possibly be valid are refused.
+func checkJobPath(jobName string) error {
+	segments := strings.Split(strings.Trim(jobName, "/"), "/")
+	for _, segment := range segments {
+		if reason := structuralProblem(segment); reason != "" {
+			return &InvalidJobNameError{Name: jobName, Reason: reason}
+		}
+	}
+	return nil
+}
+
+// checkNewJobName checks jobName, the path of a job to be created or the new name of a job, against the rules
+// Jenkins applies to item names.
+func checkNewJobName(jobName string) error {
+	if err := checkJobPath(jobName); err != nil {
+		return err
+	}
+	name := JobPath(jobName).Name()
+	if strings.TrimSpace(name) != name {
+		return &InvalidJobNameError{Name: jobName, Reason: "leading or trailing whitespace"}
+	}
+	if i := strings.IndexAny(name, unsafeNameCharacters); i >= 0 {
+		return &InvalidJobNameError{Name: jobName, Reason: fmt.Sprintf("%q is an unsafe character", name[i])}
+	}
+	if strings.HasSuffix(name, ".") {
+		return &InvalidJobNameError{Name: jobName, Reason: "a name cannot end with '.'"}
+	}
+	return nil
+}
+
+// structuralProblem returns why segment cannot be the name of an item, or "" if it can be.
+func structuralProblem(segment string) string {
+	switch segment {
+	case "":
+		return "a name cannot be empty"
+	case ".", "..":
+		return fmt.Sprintf("%q is not an allowed name", segment)
+	}
+	for _, r := range segment {
+		if unicode.IsControl(r) {
+			return "a name cannot contain control characters"
+		}
+	}
+	return ""
+}
+
+// ValidateJobName is ValidateJobNameContext with a background context.
+func (client Client) ValidateJobName(jobName string) error {
+	return client.ValidateJobNameContext(context.Background(), jobName)
+}
+
+// ValidateJobNameContext checks whether a job could be created at jobName, and returns an *InvalidJobNameError
+// with Jenkins' reason if not.  Names that break Jenkins' naming rules are rejected without a request, as
+// CreateJob does; other names are checked by the checkJobName endpoint of the folder the job would go in, which
+// also knows about taken names and rules added by plugins.  Call it before CreateJob to learn why a name would
+// be refused.
+func (client Client) ValidateJobNameContext(ctx context.Context, jobName string) error {
+	ctx = withJob(ctx, jobName)
+	if err := checkNewJobName(jobName); err != nil {
+		return err
+	}
+	path := JobPath(jobName)
+	var reason string
+	work := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", client.resourceURL(string(path.Parent()), "checkJobName", url.Values{"value": {path.Name()}}), nil)
+		if err != nil {
+			return err
+		}
+
+		response, data, err := client.consumeResponse(req)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode != http.StatusOK {
+			return newAPIError(req, response, data, jobName)
+		}
+		reason = formValidationError(data)
+		return nil
+	}
+	if err := client.try(ctx, work); err != nil {
+		return err
+	}
+	if reason != "" {
+		return &InvalidJobNameError{Name: jobName, Reason: reason}
+	}
+	return nil
+}
+
+// formValidationError returns the message of a Jenkins form validation response such as
+// <div class=error><img src="..."> A job already exists with the name &lsquo;x&rsquo;</div>, or "" if the
+// response is not an error.  Warnings do not count as errors.
+func formValidationError(body []byte) string {
+	document := string(body)
+	if !strings.Contains(document, "class=error") && !strings.Contains(document, `class="error"`) {
+		return ""
+	}
+	var text strings.Builder
+	inTag := false
+	for _, r := range document {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			text.WriteRune(r)
+		}
+	}
+	reason := strings.TrimSpace(html.UnescapeString(text.String()))
+	if reason == "" {
+		return "rejected by Jenkins"
+	}
+	return reason
+}
@@ -0,0 +1,154 @@
+package jenkins
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// awkwardJobNames maps job paths to the escaped URL path of their config.xml.
+var awkwardJobNames = map[string]string{
+	"Jenkins Demo":     "/job/Jenkins%20Demo/config.xml",
+	"C# build":         "/job/C%23%20build/config.xml",
+	"100% coverage":    "/job/100%25%20coverage/config.xml",
+	"R&D":              "/job/R&D/config.xml",
+	"a+b":              "/job/a+b/config.xml",
+	"what?":            "/job/what%3F/config.xml",
+	"déploiement":      "/job/d%C3%A9ploiement/config.xml",
+	"日本語":              "/job/%E6%97%A5%E6%9C%AC%E8%AA%9E/config.xml",
+	"team/my service":  "/job/team/job/my%20service/config.xml",
+	"Q&A/release #1.0": "/job/Q&A/job/release%20%231.0/config.xml",
+}
+
+func TestAwkwardJobNamesAreEscaped(t *testing.T) {
+	for jobName, wantEscaped := range awkwardJobNames {
+		wantPath, _ := url.PathUnescape(wantEscaped)
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != wantPath {
+				t.Errorf("Want path %s for job %q but got %s\n", wantPath, jobName, r.URL.Path)
+			}
+			if r.URL.EscapedPath() != wantEscaped {
+				t.Errorf("Want escaped path %s for job %q but got %s\n", wantEscaped, jobName, r.URL.EscapedPath())
+			}
+			fmt.Fprintln(w, jobConfig)
+		}))
+
+		url, _ := url.Parse(testServer.URL)
+		jenkinsClient := NewClient(url, "u", "p")
+		if _, err := jenkinsClient.GetJobConfig(jobName); err != nil {
+			t.Errorf("Unexpected error for job %q: %v\n", jobName, err)
+		}
+		testServer.Close()
+	}
+}
+
+func TestCreateJobNameIsQueryEscaped(t *testing.T) {
+	for _, jobName := range []string{"my job", "a+b=c", "déploiement", "日本語", "v1.0 (beta)"} {
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/createItem" {
+				t.Errorf("Want /createItem but got %s\n", r.URL.Path)
+			}
+			if got := r.URL.Query()["name"]; len(got) != 1 || got[0] != jobName {
+				t.Errorf("Want name %q but got %q\n", jobName, got)
+			}
+			if len(r.URL.Query()) != 1 {
+				t.Errorf("Want only the name parameter but got %v\n", r.URL.Query())
+			}
+		}))
+
+		url, _ := url.Parse(testServer.URL)
+		jenkinsClient := NewClient(url, "u", "p")
+		if err := jenkinsClient.CreateJob(jobName, fooJob); err != nil {
+			t.Errorf("Unexpected error for job %q: %v\n", jobName, err)
+		}
+		testServer.Close()
+	}
+}
+
+func TestInvalidNamesRejectedBeforeRequest(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("Not expecting a request, but got %s %s\n", r.Method, r.URL)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	for _, jobName := range []string{"", "..", "team/.", "team//service", "R&D", "100%", "C#", "a:b", "trailing.", " padded", "tab\there", "what?"} {
+		err := jenkinsClient.CreateJob(jobName, fooJob)
+		var nameErr *InvalidJobNameError
+		if !errors.As(err, &nameErr) || !errors.Is(err, ErrInvalidJobName) {
+			t.Fatalf("Want an *InvalidJobNameError for %q but got %v\n", jobName, err)
+		}
+	}
+	for _, jobName := range []string{"", "..", "team/..", "a\x00b"} {
+		if _, err := jenkinsClient.GetJobConfig(jobName); !errors.Is(err, ErrInvalidJobName) {
+			t.Fatalf("Want ErrInvalidJobName for %q but got %v\n", jobName, err)
+		}
+	}
+}
+
+func TestResourceURL(t *testing.T) {
+	for base, want := range map[string]string{
+		"https://ci.example.com":          "https://ci.example.com/job/team/job/a%20b/lastBuild/api/json?depth=1&tree=timestamp%2Cresult",
+		"https://ci.example.com/jenkins/": "https://ci.example.com/jenkins/job/team/job/a%20b/lastBuild/api/json?depth=1&tree=timestamp%2Cresult",
+	} {
+		baseURL, _ := url.Parse(base)
+		jenkinsClient := NewClient(baseURL, "u", "p").(Client)
+		got := jenkinsClient.resourceURL("team/a b", "lastBuild/api/json", url.Values{"tree": {"timestamp,result"}, "depth": {"1"}})
+		if got != want {
+			t.Fatalf("Want %s but got %s\n", want, got)
+		}
+		if root := jenkinsClient.resourceURL("", "crumbIssuer/api/json", nil); !strings.HasSuffix(root, ".com/crumbIssuer/api/json") && !strings.HasSuffix(root, "/jenkins/crumbIssuer/api/json") {
+			t.Fatalf("Want the crumb issuer below the base URL but got %s\n", root)
+		}
+	}
+}
+
+func TestValidateJobName(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/team/checkJobName" {
+			t.Fatalf("wanted URL path /job/team/checkJobName but found %s\n", r.URL.Path)
+		}
+		switch r.URL.Query().Get("value") {
+		case "taken":
+			fmt.Fprint(w, `<div class=error><img src="/static/error.png" height=16 width=16>A job already exists with the name &lsquo;taken&rsquo;</div>`)
+		case "free":
+			fmt.Fprint(w, `<div/>`)
+		default:
+			t.Fatalf("unexpected query %s\n", r.URL.RawQuery)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.ValidateJobName("team/free"); err != nil {
+		t.Fatalf("not expecting an error, but received: %v\n", err)
+	}
+
+	err := jenkinsClient.ValidateJobName("team/taken")
+	var nameErr *InvalidJobNameError
+	if !errors.As(err, &nameErr) {
+		t.Fatalf("want an *InvalidJobNameError but got %v\n", err)
+	}
+	if nameErr.Reason != "A job already exists with the name ‘taken’" {
+		t.Fatalf("unexpected reason %q\n", nameErr.Reason)
+	}
+}
+
+func TestValidateJobNameLocally(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s\n", r.Method, r.URL.Path)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	jenkinsClient := NewClient(url, "u", "p")
+	if err := jenkinsClient.ValidateJobName("a:b"); !errors.Is(err, ErrInvalidJobName) {
+		t.Fatalf("want an invalid job name error but got %v\n", err)
+	}
+}
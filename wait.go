@@ -0,0 +1,81 @@
+package jenkins
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultPollInterval is how long waiting for a build first waits between polls.
+	DefaultPollInterval = time.Second
+
+	// DefaultMaxPollInterval is the longest waiting for a build waits between polls.
+	DefaultMaxPollInterval = 15 * time.Second
+)
+
+// WaitOption configures how WaitForBuildStart, WaitForBuild and StreamConsole wait.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+	queueProgress   func(QueueItem)
+	buildProgress   func(BuildProgress)
+}
+
+// WithPollInterval makes waiting poll Jenkins first after initial, and then at intervals that double up to max.
+func WithPollInterval(initial, max time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.pollInterval = initial
+		o.maxPollInterval = max
+	}
+}
+
+// WithQueueProgress makes WaitForBuildStart call report with the state of the queue item after every poll that
+// finds it still waiting, for example to show its Why.
+func WithQueueProgress(report func(QueueItem)) WaitOption {
+	return func(o *waitOptions) {
+		o.queueProgress = report
+	}
+}
+
+// WithBuildProgress makes WaitForBuild call report with the progress of the build after every poll that finds it
+// still running.
+func WithBuildProgress(report func(BuildProgress)) WaitOption {
+	return func(o *waitOptions) {
+		o.buildProgress = report
+	}
+}
+
+func newWaitOptions(opts []WaitOption) waitOptions {
+	o := waitOptions{pollInterval: DefaultPollInterval, maxPollInterval: DefaultMaxPollInterval}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.pollInterval <= 0 {
+		o.pollInterval = DefaultPollInterval
+	}
+	if o.maxPollInterval < o.pollInterval {
+		o.maxPollInterval = o.pollInterval
+	}
+	return o
+}
+
+// poll calls check until it reports done or fails, waiting between calls as configured by o.  It gives up with
+// ctx's error once ctx is done.
+func (o waitOptions) poll(ctx context.Context, check func() (bool, error)) error {
+	interval := o.pollInterval
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+		interval *= 2
+		if interval > o.maxPollInterval {
+			interval = o.maxPollInterval
+		}
+	}
+}